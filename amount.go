@@ -0,0 +1,135 @@
+package goar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/permadao/goar/utils"
+)
+
+// Amount of AR held as integer winston, it is immutable and the zero value is 0 winston.
+type Amount struct {
+	w *big.Int
+}
+
+// NewAmount from winston
+func NewAmount(winston *big.Int) Amount {
+	if winston == nil {
+		return Amount{}
+	}
+	return Amount{w: new(big.Int).Set(winston)}
+}
+
+func NewWinstonAmount(winston int64) Amount {
+	return Amount{w: big.NewInt(winston)}
+}
+
+// ParseAR parses a decimal AR value, eg: "0.1"
+func ParseAR(ar string) (Amount, error) {
+	w, err := utils.ARStringToWinston(ar)
+	if err != nil {
+		return Amount{}, err
+	}
+	return Amount{w: w}, nil
+}
+
+// ParseWinston parses an integer winston value, eg: the quantity or reward of a tx
+func ParseWinston(winston string) (Amount, error) {
+	w, ok := new(big.Int).SetString(winston, 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid winston amount: %s", winston)
+	}
+	return Amount{w: w}, nil
+}
+
+// ParseAmount parses a value with its unit, eg: "1.5 AR", "1500000000000 winston"
+func ParseAmount(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasSuffix(lower, "winston"):
+		return ParseWinston(strings.TrimSpace(s[:len(s)-len("winston")]))
+	case strings.HasSuffix(lower, "ar"):
+		return ParseAR(strings.TrimSpace(s[:len(s)-len("ar")]))
+	default:
+		return Amount{}, fmt.Errorf("amount unit must be AR or winston: %s", s)
+	}
+}
+
+func (a Amount) int() *big.Int {
+	if a.w == nil {
+		return new(big.Int)
+	}
+	return a.w
+}
+
+// Winston returns a copy of the winston value
+func (a Amount) Winston() *big.Int {
+	return new(big.Int).Set(a.int())
+}
+
+// AR exact decimal AR value, eg: "0.1"
+func (a Amount) AR() string {
+	return utils.WinstonToARString(a.int())
+}
+
+func (a Amount) String() string {
+	return a.AR() + " AR"
+}
+
+func (a Amount) Add(b Amount) Amount {
+	return Amount{w: new(big.Int).Add(a.int(), b.int())}
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	return Amount{w: new(big.Int).Sub(a.int(), b.int())}
+}
+
+// Mul a * m rounded down to a winston, eg: big.NewRat(11, 10) for 1.1 * a
+func (a Amount) Mul(m *big.Rat) Amount {
+	return Amount{w: utils.MulReward(a.int(), m)}
+}
+
+// Cmp returns -1, 0 or +1 as a < b, a == b or a > b
+func (a Amount) Cmp(b Amount) int {
+	return a.int().Cmp(b.int())
+}
+
+func (a Amount) Sign() int {
+	return a.int().Sign()
+}
+
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// MarshalJSON encodes the winston value as a string, like the quantity and reward of a tx
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.int().String())
+}
+
+// UnmarshalJSON accepts a winston number or string, or a string with unit, eg: "1.5 AR".
+// null is a no-op as for the types of encoding/json.
+func (a *Amount) UnmarshalJSON(data []byte) (err error) {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) == 0 || data[0] != '"' {
+		*a, err = ParseWinston(string(data))
+		return
+	}
+	var s string
+	if err = json.Unmarshal(data, &s); err != nil {
+		return
+	}
+	if strings.IndexFunc(strings.TrimPrefix(s, "-"), func(r rune) bool { return r < '0' || r > '9' }) < 0 {
+		*a, err = ParseWinston(s)
+	} else {
+		*a, err = ParseAmount(s)
+	}
+	return
+}
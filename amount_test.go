@@ -0,0 +1,80 @@
+package goar
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	a, err := ParseAmount("1.5 AR")
+	assert.NoError(t, err)
+	assert.Equal(t, "1500000000000", a.Winston().String())
+	assert.Equal(t, "1.5 AR", a.String())
+
+	b, err := ParseAmount("1500000000000 winston")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, a.Cmp(b))
+
+	c, err := ParseAmount("0.1ar")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.1", c.AR())
+
+	_, err = ParseAmount("1.5")
+	assert.Error(t, err)
+	_, err = ParseAmount("1.5 winston")
+	assert.Error(t, err)
+	_, err = ParseAR("0.0000000000001")
+	assert.Error(t, err)
+}
+
+func TestAmount_Arithmetic(t *testing.T) {
+	a, _ := ParseAR("0.1")
+	b, _ := ParseAR("0.2")
+	sum := a.Add(b)
+	assert.Equal(t, "0.3", sum.AR())
+	assert.Equal(t, "-0.1", a.Sub(b).AR())
+	assert.Equal(t, 1, b.Cmp(a))
+	assert.Equal(t, "0.33", sum.Mul(big.NewRat(11, 10)).AR())
+	assert.Equal(t, "333333333333", NewWinstonAmount(1000000000000).Mul(big.NewRat(1, 3)).Winston().String())
+
+	var zero Amount
+	assert.True(t, zero.IsZero())
+	assert.Equal(t, "0 AR", zero.String())
+	assert.Equal(t, "0.1", zero.Add(a).AR())
+
+	// amounts are immutable
+	w := a.Winston()
+	w.SetInt64(0)
+	assert.Equal(t, "0.1", a.AR())
+}
+
+func TestAmount_JSON(t *testing.T) {
+	type payment struct {
+		Quantity Amount `json:"quantity"`
+	}
+	a, _ := ParseAR("1.5")
+	by, err := json.Marshal(payment{Quantity: a})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"quantity":"1500000000000"}`, string(by))
+
+	for _, s := range []string{`{"quantity":"1500000000000"}`, `{"quantity":1500000000000}`, `{"quantity":"1.5 AR"}`} {
+		p := payment{}
+		assert.NoError(t, json.Unmarshal([]byte(s), &p))
+		assert.Equal(t, 0, a.Cmp(p.Quantity), s)
+	}
+	p := payment{}
+	assert.Error(t, json.Unmarshal([]byte(`{"quantity":"1.5"}`), &p))
+
+	// null leaves the amount unchanged
+	p = payment{Quantity: a}
+	assert.NoError(t, json.Unmarshal([]byte(`{"quantity":null}`), &p))
+	assert.Equal(t, 0, a.Cmp(p.Quantity))
+	optional := struct {
+		Fee *Amount `json:"fee"`
+	}{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"fee":null}`), &optional))
+	assert.Nil(t, optional.Fee)
+}
@@ -37,11 +37,11 @@ func (b *Bundler) Sign(item *schema.BundleItem) error {
 	var sigData []byte
 	switch b.SignType {
 	case schema.ArweaveSignType:
-		arSigner, ok := b.signer.(*Signer)
+		arSigner, ok := b.signer.(TxSigner)
 		if !ok {
 			return errors.New("signer must be goar signer")
 		}
-		sigData, err = utils.Sign(signMsg, arSigner.PrvKey)
+		sigData, err = arSigner.SignMsg(signMsg)
 		if err != nil {
 			return err
 		}
@@ -55,8 +55,25 @@ func (b *Bundler) Sign(item *schema.BundleItem) error {
 		if err != nil {
 			return err
 		}
+	case schema.TypedEthereumSignType:
+		typedSigner, ok := b.signer.(*TypedEthereumSigner)
+		if !ok {
+			return errors.New("signer not typed ethereum signer")
+		}
+		sigData, err = typedSigner.SignMsg(signMsg)
+		if err != nil {
+			return err
+		}
+	case schema.ED25519SignType, schema.SolanaSignType, schema.AptosSignType:
+		edSigner, ok := b.signer.(*Ed25519Signer)
+		if !ok {
+			return errors.New("signer not ed25519 signer")
+		}
+		sigData, err = edSigner.SignMsg(signMsg)
+		if err != nil {
+			return err
+		}
 	default:
-		// todo come soon support ed25519
 		return errors.New("not support this signType")
 	}
 	id := sha256.Sum256(sigData)
@@ -103,9 +120,9 @@ func (b *Bundler) CreateAndSignNestedItem(target string, anchor string, tags []s
 }
 
 func reflectSigner(signer interface{}) (signType int, signerAddr, owner string, err error) {
-	if s, ok := signer.(*Signer); ok {
+	if s, ok := signer.(TxSigner); ok {
 		signType = schema.ArweaveSignType
-		signerAddr = s.Address
+		signerAddr, err = TxSignerAddress(s)
 		owner = s.Owner()
 		return
 	}
@@ -115,6 +132,18 @@ func reflectSigner(signer interface{}) (signType int, signerAddr, owner string,
 		owner = utils.Base64Encode(s.GetPublicKey())
 		return
 	}
+	if s, ok := signer.(*TypedEthereumSigner); ok {
+		signType = schema.TypedEthereumSignType
+		signerAddr = s.Address()
+		owner = s.Owner()
+		return
+	}
+	if s, ok := signer.(*Ed25519Signer); ok {
+		signType = s.SignType
+		signerAddr = s.Address()
+		owner = s.Owner()
+		return
+	}
 	err = errors.New("not support this signer")
 	return
 }
@@ -328,6 +328,18 @@ func (c *Client) GetTransactionDataStreamByGateway(id string) (*os.File, error)
 }
 
 func (c *Client) GetTransactionPrice(dataSize int, target *string) (reward int64, err error) {
+	price, err := c.GetTransactionPriceWinston(dataSize, target)
+	if err != nil {
+		return
+	}
+	if !price.IsInt64() {
+		return 0, fmt.Errorf("reward overflows int64: %s", price)
+	}
+	return price.Int64(), nil
+}
+
+// GetTransactionPriceWinston exact reward in winston, the new wallet fee is included when target is not seen on chain
+func (c *Client) GetTransactionPriceWinston(dataSize int, target *string) (*big.Int, error) {
 	url := fmt.Sprintf("price/%d", dataSize)
 	if target != nil {
 		url = fmt.Sprintf("%v/%v", url, *target)
@@ -335,25 +347,25 @@ func (c *Client) GetTransactionPrice(dataSize int, target *string) (reward int64
 
 	body, code, err := c.httpGet(url)
 	if code == 429 {
-		return 0, schema.ErrRequestLimit
+		return nil, schema.ErrRequestLimit
 	}
 	if err != nil {
-		return
+		return nil, err
 	}
 	if code != 200 {
-		return 0, fmt.Errorf("get reward error: %s", string(body))
+		return nil, fmt.Errorf("get reward error: %s", string(body))
 	}
 
-	reward, err = strconv.ParseInt(string(body), 10, 64)
-	if err != nil {
-		return
+	reward, ok := new(big.Int).SetString(string(body), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid reward: %s", string(body))
 	}
 
 	// reward can not be 0
-	if reward <= 0 {
-		err = errors.New("reward must more than 0")
+	if reward.Sign() <= 0 {
+		return nil, errors.New("reward must more than 0")
 	}
-	return
+	return reward, nil
 }
 
 func (c *Client) GetTransactionAnchor() (anchor string, err error) {
@@ -470,6 +482,14 @@ func (c *Client) GetWalletWinstonBalance(address string) (arAmount *big.Int, err
 	return
 }
 
+func (c *Client) GetWalletAmount(address string) (Amount, error) {
+	winston, err := c.GetWalletWinstonBalance(address)
+	if err != nil {
+		return Amount{}, err
+	}
+	return Amount{w: winston}, nil
+}
+
 func (c *Client) GetLastTransactionID(address string) (id string, err error) {
 	body, code, err := c.httpGet(fmt.Sprintf("wallet/%s/last_tx", address))
 	if code == 429 {
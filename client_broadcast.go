@@ -128,3 +128,36 @@ func (c *Client) GetUnconfirmedTxFromPeers(arId string, peers ...string) (*schem
 
 	return nil, fmt.Errorf("get unconfirmed tx failed; arId: %s", arId)
 }
+
+// BroadcastTx posts the signed tx header and its chunks to numOfNodes peers.
+// Unlike BroadcastData the peers do not need to know the tx already.
+func (c *Client) BroadcastTx(tx *schema.Transaction, numOfNodes int64, peers ...string) error {
+	var err error
+	if len(peers) == 0 {
+		peers, err = c.GetPeers()
+		if err != nil {
+			return err
+		}
+	}
+
+	count := int64(0)
+	pNode := NewTempConn()
+	for _, peer := range peers {
+		pNode.SetTempConnUrl("http://" + peer)
+		uploader, err := CreateUploader(pNode, tx, nil)
+		if err != nil {
+			return err
+		}
+
+		if err = uploader.Once(); err != nil {
+			continue
+		}
+
+		count++
+		if count >= numOfNodes {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("broadcast tx to peers failed, txId: %s", tx.ID)
+}
@@ -0,0 +1,305 @@
+package goar
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/panjf2000/ants/v2"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+)
+
+var (
+	confirmPollMinInterval = 5 * time.Second
+	confirmPollMaxInterval = 2 * time.Minute
+	// checks of a tx with an unknown anchor which is never found before it is reported as not found
+	confirmMaxNotFound = 10
+	// consecutive gateway errors before they are returned
+	confirmMaxFailures = 5
+)
+
+// WaitForConfirmation polls the status of a submitted tx with backoff until it has minConfirmations.
+// It returns schema.ErrTxDropped when the tx is no longer pending and its anchor block left the
+// 50-block window, as the tx can not be mined anymore. The anchor is learnt once the tx is seen pending,
+// schema.ErrNotFound is returned when the node never knows the tx, use WaitForTxConfirmation when the tx is at hand.
+func (c *Client) WaitForConfirmation(ctx context.Context, txID string, minConfirmations int) (*schema.TxStatus, error) {
+	return c.waitForConfirmation(ctx, newTxTracker(txID), minConfirmations)
+}
+
+// WaitForTxConfirmation as WaitForConfirmation, the anchor is taken from the submitted tx
+// so a tx which never reaches the node is reported as dropped.
+func (c *Client) WaitForTxConfirmation(ctx context.Context, tx *schema.Transaction, minConfirmations int) (*schema.TxStatus, error) {
+	return c.waitForConfirmation(ctx, newTxTrackerOf(tx), minConfirmations)
+}
+
+func (c *Client) waitForConfirmation(ctx context.Context, t *txTracker, minConfirmations int) (*schema.TxStatus, error) {
+	anchors := &anchorCache{heights: make(map[string]int64)}
+	for {
+		status, done, err := c.checkTx(t, minConfirmations, anchors, nil)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return status, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(t.backoff()):
+		}
+	}
+}
+
+// TxWatcher tracks the confirmation of many txs, the network height and the anchor heights
+// are fetched once per round for all of them.
+type TxWatcher struct {
+	client           *Client
+	minConfirmations int
+
+	lock     sync.Mutex
+	trackers map[string]*txTracker
+}
+
+func NewTxWatcher(client *Client, minConfirmations int) *TxWatcher {
+	return &TxWatcher{
+		client:           client,
+		minConfirmations: minConfirmations,
+		trackers:         make(map[string]*txTracker),
+	}
+}
+
+func (w *TxWatcher) Add(txIDs ...string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, id := range txIDs {
+		if _, ok := w.trackers[id]; !ok {
+			w.trackers[id] = newTxTracker(id)
+		}
+	}
+}
+
+// AddTx watches submitted txs, their anchor is known from the start
+func (w *TxWatcher) AddTx(txs ...*schema.Transaction) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, tx := range txs {
+		if _, ok := w.trackers[tx.ID]; !ok {
+			w.trackers[tx.ID] = newTxTrackerOf(tx)
+		}
+	}
+}
+
+func (w *TxWatcher) Remove(txID string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.trackers, txID)
+}
+
+// Len number of txs still watched
+func (w *TxWatcher) Len() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return len(w.trackers)
+}
+
+// Run emits a result for every watched tx once it is confirmed, dropped or fails, the tx is then no longer watched.
+// Txs can be added while running, the channel is closed when ctx is done.
+func (w *TxWatcher) Run(ctx context.Context) <-chan schema.TxWatchResult {
+	ch := make(chan schema.TxWatchResult)
+	go func() {
+		defer close(ch)
+		anchors := &anchorCache{heights: make(map[string]int64)}
+		for {
+			w.round(ctx, anchors, ch)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(confirmPollMinInterval):
+			}
+		}
+	}()
+	return ch
+}
+
+func (w *TxWatcher) round(ctx context.Context, anchors *anchorCache, ch chan<- schema.TxWatchResult) {
+	now := time.Now()
+	due := make([]*txTracker, 0)
+	w.lock.Lock()
+	for _, t := range w.trackers {
+		if !now.Before(t.nextCheck) {
+			due = append(due, t)
+		}
+	}
+	w.lock.Unlock()
+	if len(due) == 0 {
+		return
+	}
+
+	var height *int64
+	if info, err := w.client.GetInfo(); err == nil {
+		height = &info.Height
+	} else {
+		log.Warn("tx watcher get info failed", "err", err)
+	}
+
+	results := make(chan schema.TxWatchResult, len(due))
+	var wg sync.WaitGroup
+	p, err := ants.NewPoolWithFunc(schema.DEFAULT_TX_WATCH_CONCURRENT_NUM, func(i interface{}) {
+		defer wg.Done()
+		t := i.(*txTracker)
+		status, done, err := w.client.checkTx(t, w.minConfirmations, anchors, height)
+		if done || err != nil {
+			results <- schema.TxWatchResult{TxId: t.id, Status: status, Err: err}
+			return
+		}
+		t.nextCheck = time.Now().Add(t.backoff())
+	})
+	if err != nil {
+		log.Error("tx watcher new pool failed", "err", err)
+		return
+	}
+	defer p.Release()
+	for _, t := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		if err = p.Invoke(t); err != nil {
+			wg.Done()
+			log.Error("p.Invoke(t)", "err", err, "txId", t.id)
+			break
+		}
+	}
+	wg.Wait()
+	close(results)
+
+	for res := range results {
+		w.Remove(res.TxId)
+		select {
+		case ch <- res:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+type txTracker struct {
+	id        string
+	anchor    string // last_tx of the tx, learnt once seen pending when not submitted by us
+	interval  time.Duration
+	nextCheck time.Time
+	notFound  int // checks not finding the tx while its anchor is unknown
+	failures  int // consecutive gateway errors
+}
+
+func newTxTracker(id string) *txTracker {
+	return &txTracker{id: id}
+}
+
+func newTxTrackerOf(tx *schema.Transaction) *txTracker {
+	return &txTracker{id: tx.ID, anchor: tx.LastTx}
+}
+
+// fail returns err once it persisted for confirmMaxFailures checks
+func (t *txTracker) fail(err error) error {
+	t.failures++
+	if t.failures >= confirmMaxFailures {
+		return err
+	}
+	return nil
+}
+
+func (t *txTracker) backoff() time.Duration {
+	if t.interval == 0 {
+		t.interval = confirmPollMinInterval
+	} else if t.interval *= 2; t.interval > confirmPollMaxInterval {
+		t.interval = confirmPollMaxInterval
+	}
+	return t.interval
+}
+
+// anchorCache heights of anchor blocks, shared by the txs of a watcher
+type anchorCache struct {
+	lock    sync.Mutex
+	heights map[string]int64
+}
+
+// height of the anchor block, -1 when the anchor is the last tx of the wallet which does not expire
+func (a *anchorCache) height(c *Client, anchor string) (int64, error) {
+	a.lock.Lock()
+	h, ok := a.heights[anchor]
+	a.lock.Unlock()
+	if ok {
+		return h, nil
+	}
+	if by, err := utils.Base64Decode(anchor); err != nil || len(by) != 48 {
+		h = -1
+	} else {
+		b, err := c.GetBlockByID(anchor)
+		if err != nil {
+			return 0, err
+		}
+		h = b.Height
+	}
+	a.lock.Lock()
+	a.heights[anchor] = h
+	a.lock.Unlock()
+	return h, nil
+}
+
+// checkTx returns done when the tx has minConfirmations, schema.ErrTxDropped when it can no longer be mined.
+// schema.ErrNotFound is returned when the anchor is unknown and the tx is not found for confirmMaxNotFound checks.
+// Gateway errors are logged and the tx is checked again later, they are returned after confirmMaxFailures
+// consecutive checks. height is fetched when nil, before the tx status so a tx mined in between is not dropped.
+func (c *Client) checkTx(t *txTracker, minConfirmations int, anchors *anchorCache, height *int64) (*schema.TxStatus, bool, error) {
+	if height == nil {
+		info, err := c.GetInfo()
+		if err != nil {
+			log.Warn("get info failed", "err", err, "txId", t.id)
+			return nil, false, t.fail(err)
+		}
+		height = &info.Height
+	}
+	status, err := c.GetTransactionStatus(t.id)
+	switch err {
+	case nil:
+		t.failures = 0
+		if status.NumberOfConfirmations >= minConfirmations {
+			return status, true, nil
+		}
+		// mined, the anchor is no longer relevant
+		return status, false, nil
+	case schema.ErrPendingTx:
+		if t.anchor == "" {
+			if tx, err := c.GetUnconfirmedTx(t.id); err == nil {
+				t.anchor = tx.LastTx
+			}
+		}
+	case schema.ErrNotFound:
+		if t.anchor == "" {
+			if t.notFound++; t.notFound >= confirmMaxNotFound {
+				return nil, false, schema.ErrNotFound
+			}
+		}
+	default:
+		log.Warn("get tx status failed", "err", err, "txId", t.id)
+		return nil, false, t.fail(err)
+	}
+
+	if t.anchor == "" {
+		t.failures = 0
+		return nil, false, nil
+	}
+	anchorHeight, err := anchors.height(c, t.anchor)
+	if err != nil {
+		log.Warn("get anchor block failed", "err", err, "txId", t.id)
+		return nil, false, t.fail(err)
+	}
+	t.failures = 0
+	if anchorHeight >= 0 && *height > anchorHeight+schema.MAX_TX_ANCHOR_DEPTH {
+		return nil, false, schema.ErrTxDropped
+	}
+	return nil, false, nil
+}
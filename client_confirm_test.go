@@ -0,0 +1,255 @@
+package goar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	// set once, watchers of finished tests may still be polling
+	confirmPollMinInterval = 10 * time.Millisecond
+	confirmPollMaxInterval = 20 * time.Millisecond
+}
+
+// testTxNode serves /info, tx statuses, unconfirmed txs and anchor blocks, submitted txs become pending
+type testTxNode struct {
+	lock     sync.Mutex
+	height   int64
+	statuses map[string]*schema.TxStatus // nil status means pending
+	anchors  map[string]string           // tx id -> last_tx
+	blocks   map[string]int64            // anchor block -> height
+
+	anchor    string // served by /tx_anchor
+	submitted []*schema.Transaction
+	txs       map[string]*schema.Transaction // unconfirmed tx headers, defaults to id and last_tx
+	balance   string                         // of every wallet
+	failing   bool                           // tx statuses fail with a bad gateway
+	onStatus  func(id string)                // called once a tx status is served
+}
+
+func (n *testTxNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodPost && parts[0] == "tx":
+		tx := &schema.Transaction{}
+		json.NewDecoder(r.Body).Decode(tx)
+		n.submitted = append(n.submitted, tx)
+		n.statuses[tx.ID] = nil
+		n.anchors[tx.ID] = tx.LastTx
+	case r.Method == http.MethodPost && parts[0] == "chunk":
+	case parts[0] == "tx" && len(parts) == 2 && parts[1] == "pending":
+		ids := make([]string, 0)
+		for id, status := range n.statuses {
+			if status == nil {
+				ids = append(ids, id)
+			}
+		}
+		json.NewEncoder(w).Encode(ids)
+	case parts[0] == "tx_anchor":
+		w.Write([]byte(n.anchor))
+	case parts[0] == "wallet" && len(parts) == 3 && parts[2] == "balance":
+		w.Write([]byte(n.balance))
+	case parts[0] == "price":
+		w.Write([]byte("1000"))
+	case parts[0] == "info":
+		json.NewEncoder(w).Encode(schema.NetworkInfo{Height: n.height})
+	case parts[0] == "tx" && len(parts) == 3 && parts[2] == "status":
+		if n.failing {
+			w.WriteHeader(502)
+			return
+		}
+		if n.onStatus != nil {
+			defer n.onStatus(parts[1])
+		}
+		status, ok := n.statuses[parts[1]]
+		if !ok {
+			w.WriteHeader(404)
+			return
+		}
+		if status == nil {
+			w.WriteHeader(202)
+			return
+		}
+		json.NewEncoder(w).Encode(status)
+	case parts[0] == "unconfirmed_tx":
+		status, ok := n.statuses[parts[1]]
+		if !ok || status != nil {
+			w.WriteHeader(404)
+			return
+		}
+		if tx, ok := n.txs[parts[1]]; ok {
+			json.NewEncoder(w).Encode(tx)
+			return
+		}
+		json.NewEncoder(w).Encode(schema.Transaction{ID: parts[1], LastTx: n.anchors[parts[1]]})
+	case parts[0] == "block" && parts[1] == "hash":
+		h, ok := n.blocks[parts[2]]
+		if !ok {
+			w.WriteHeader(404)
+			return
+		}
+		json.NewEncoder(w).Encode(schema.Block{Height: h, IndepHash: parts[2]})
+	default:
+		w.WriteHeader(404)
+	}
+}
+
+func (n *testTxNode) set(f func()) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	f()
+}
+
+func TestClient_WaitForConfirmation(t *testing.T) {
+	anchor := utils.Base64Encode(make([]byte, 48))
+	node := &testTxNode{
+		height:   100,
+		statuses: map[string]*schema.TxStatus{"tx1": nil, "tx2": nil},
+		anchors:  map[string]string{"tx1": anchor, "tx2": anchor},
+		blocks:   map[string]int64{anchor: 90},
+	}
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		node.set(func() {
+			node.statuses["tx1"] = &schema.TxStatus{BlockHeight: 101, BlockIndepHash: "b101", NumberOfConfirmations: 1}
+		})
+		time.Sleep(50 * time.Millisecond)
+		node.set(func() { node.statuses["tx1"].NumberOfConfirmations = 3 })
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := c.WaitForConfirmation(ctx, "tx1", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 101, status.BlockHeight)
+	assert.Equal(t, "b101", status.BlockIndepHash)
+
+	// tx2 never mined, it leaves the mempool once the anchor expires
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		node.set(func() {
+			node.height = 141
+			delete(node.statuses, "tx2")
+		})
+	}()
+	_, err = c.WaitForConfirmation(ctx, "tx2", 1)
+	assert.Equal(t, schema.ErrTxDropped, err)
+}
+
+func TestClient_WaitForConfirmation_NeverSeen(t *testing.T) {
+	anchor := utils.Base64Encode(make([]byte, 48))
+	node := &testTxNode{
+		height:   141,
+		statuses: map[string]*schema.TxStatus{},
+		anchors:  map[string]string{},
+		blocks:   map[string]int64{anchor: 90},
+	}
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+	c := NewClient(srv.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the anchor of the submitted tx is known, it expired
+	_, err := c.WaitForTxConfirmation(ctx, &schema.Transaction{ID: "lost", LastTx: anchor}, 1)
+	assert.Equal(t, schema.ErrTxDropped, err)
+
+	// only the id is known, the node never saw the tx
+	_, err = c.WaitForConfirmation(ctx, "lost", 1)
+	assert.Equal(t, schema.ErrNotFound, err)
+
+	// persistent gateway errors are returned
+	node.set(func() { node.failing = true })
+	_, err = c.WaitForTxConfirmation(ctx, &schema.Transaction{ID: "lost", LastTx: anchor}, 1)
+	assert.Equal(t, schema.ErrBadGateway, err)
+}
+
+func TestClient_WaitForConfirmation_MinedAtWindowEdge(t *testing.T) {
+	anchor := utils.Base64Encode(make([]byte, 48))
+	node := &testTxNode{
+		height:   149,
+		statuses: map[string]*schema.TxStatus{"tx1": nil},
+		anchors:  map[string]string{"tx1": anchor},
+		blocks:   map[string]int64{anchor: 99},
+	}
+	// tx1 is mined in the last block of its anchor window right after its pending status is served
+	node.onStatus = func(id string) {
+		if node.statuses[id] == nil {
+			node.statuses[id] = &schema.TxStatus{BlockHeight: 150, NumberOfConfirmations: 1}
+			node.height = 150
+		}
+	}
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := NewClient(srv.URL).WaitForTxConfirmation(ctx, &schema.Transaction{ID: "tx1", LastTx: anchor}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 150, status.BlockHeight)
+}
+
+func TestTxWatcher(t *testing.T) {
+	anchor := utils.Base64Encode(make([]byte, 48))
+	node := &testTxNode{
+		height:   100,
+		statuses: map[string]*schema.TxStatus{},
+		anchors:  map[string]string{},
+		blocks:   map[string]int64{anchor: 95},
+	}
+	ids := []string{"a", "b", "c", "d"}
+	for _, id := range ids {
+		node.statuses[id] = nil
+		node.anchors[id] = anchor
+	}
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	w := NewTxWatcher(NewClient(srv.URL), 2)
+	w.Add(ids...)
+	ch := w.Run(ctx)
+
+	node.set(func() {
+		node.statuses["a"] = &schema.TxStatus{BlockHeight: 101, NumberOfConfirmations: 2}
+		node.statuses["b"] = &schema.TxStatus{BlockHeight: 101, NumberOfConfirmations: 2}
+	})
+	results := map[string]schema.TxWatchResult{}
+	for len(results) < 2 {
+		res := <-ch
+		results[res.TxId] = res
+	}
+	assert.NoError(t, results["a"].Err)
+	assert.NoError(t, results["b"].Err)
+	assert.Equal(t, 2, w.Len())
+
+	node.set(func() {
+		node.statuses["c"] = &schema.TxStatus{BlockHeight: 102, NumberOfConfirmations: 5}
+		delete(node.statuses, "d")
+		node.height = 146
+	})
+	for len(results) < 4 {
+		res := <-ch
+		results[res.TxId] = res
+	}
+	assert.NoError(t, results["c"].Err)
+	assert.Equal(t, 102, results["c"].Status.BlockHeight)
+	assert.Equal(t, schema.ErrTxDropped, results["d"].Err)
+	assert.Equal(t, 0, w.Len())
+}
@@ -0,0 +1,194 @@
+package goar
+
+import (
+	"context"
+	"time"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+)
+
+var (
+	mempoolPollInterval  = 10 * time.Second
+	mempoolFetchInterval = 100 * time.Millisecond // min delay between two unconfirmed tx requests
+)
+
+// MempoolFilter selects the txs reported by MempoolWatcher. Every non empty field must match,
+// a field matches when any of its values does. An empty filter matches all txs.
+type MempoolFilter struct {
+	Owners  []string     // wallet addresses
+	Targets []string     // wallet addresses
+	Tags    []schema.Tag // a tag with an empty value matches any value of that name
+}
+
+func (f MempoolFilter) match(tx *schema.Transaction) bool {
+	if len(f.Owners) > 0 {
+		addr, err := utils.OwnerToAddress(tx.Owner)
+		if err != nil || !utils.ContainsInSlice(f.Owners, addr) {
+			return false
+		}
+	}
+	if len(f.Targets) > 0 && !utils.ContainsInSlice(f.Targets, tx.Target) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		tags, err := utils.TagsDecode(tx.Tags)
+		if err != nil {
+			return false
+		}
+		for _, want := range f.Tags {
+			for _, tag := range tags {
+				if tag.Name == want.Name && (want.Value == "" || tag.Value == want.Value) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// MempoolWatcher diffs successive snapshots of /tx/pending and reports the matching txs
+// entering the mempool and leaving it, either mined or dropped.
+type MempoolWatcher struct {
+	client *Client
+	filter MempoolFilter
+
+	entries   map[string]*mempoolEntry // txs of the last snapshot, and matched txs until their fate is known
+	lastFetch time.Time
+}
+
+type mempoolEntry struct {
+	tx      *schema.Transaction // nil until the header is fetched
+	matched bool
+}
+
+func NewMempoolWatcher(client *Client, filter MempoolFilter) *MempoolWatcher {
+	return &MempoolWatcher{
+		client:  client,
+		filter:  filter,
+		entries: make(map[string]*mempoolEntry),
+	}
+}
+
+// Run polls the mempool until ctx is done, then the channel is closed.
+// Txs already pending on the first snapshot are reported as entered.
+func (m *MempoolWatcher) Run(ctx context.Context) <-chan schema.MempoolEvent {
+	ch := make(chan schema.MempoolEvent)
+	go func() {
+		defer close(ch)
+		for {
+			if err := m.poll(ctx, ch); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Warn("mempool watcher poll failed", "err", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(mempoolPollInterval):
+			}
+		}
+	}()
+	return ch
+}
+
+func (m *MempoolWatcher) poll(ctx context.Context, ch chan<- schema.MempoolEvent) error {
+	ids, err := m.client.GetPendingTxIds()
+	if err != nil {
+		return err
+	}
+	pending := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		pending[id] = struct{}{}
+	}
+
+	// left the mempool
+	for id, e := range m.entries {
+		if _, ok := pending[id]; ok {
+			continue
+		}
+		if !e.matched {
+			delete(m.entries, id)
+			continue
+		}
+		event, ok := m.resolve(id, e)
+		if !ok {
+			continue
+		}
+		delete(m.entries, id)
+		if err = emitMempoolEvent(ctx, ch, event); err != nil {
+			return err
+		}
+	}
+
+	// entered the mempool, or the header could not be fetched yet
+	for _, id := range ids {
+		e, ok := m.entries[id]
+		if !ok {
+			e = &mempoolEntry{}
+			m.entries[id] = e
+		}
+		if e.tx != nil {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		tx, err := m.fetch(ctx, id)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			log.Debug("get unconfirmed tx failed", "err", err, "txId", id)
+			continue
+		}
+		e.tx = tx
+		if e.matched = m.filter.match(tx); !e.matched {
+			continue
+		}
+		if err = emitMempoolEvent(ctx, ch, schema.MempoolEvent{Type: schema.MempoolEventEntered, TxId: id, Tx: tx}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve whether a tx that left the mempool was mined or dropped, false when still unknown
+func (m *MempoolWatcher) resolve(id string, e *mempoolEntry) (schema.MempoolEvent, bool) {
+	status, err := m.client.GetTransactionStatus(id)
+	switch err {
+	case nil:
+		return schema.MempoolEvent{Type: schema.MempoolEventMined, TxId: id, Tx: e.tx, Status: status}, true
+	case schema.ErrNotFound:
+		return schema.MempoolEvent{Type: schema.MempoolEventDropped, TxId: id, Tx: e.tx}, true
+	default:
+		// pending on the node but not in its snapshot yet, or a gateway error
+		return schema.MempoolEvent{}, false
+	}
+}
+
+// fetch waits mempoolFetchInterval since the last request, ctx cancels the wait
+func (m *MempoolWatcher) fetch(ctx context.Context, id string) (*schema.Transaction, error) {
+	if wait := mempoolFetchInterval - time.Since(m.lastFetch); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	m.lastFetch = time.Now()
+	return m.client.GetUnconfirmedTx(id)
+}
+
+func emitMempoolEvent(ctx context.Context, ch chan<- schema.MempoolEvent, event schema.MempoolEvent) error {
+	select {
+	case ch <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
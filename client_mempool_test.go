@@ -0,0 +1,96 @@
+package goar
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	mempoolPollInterval = 10 * time.Millisecond
+	mempoolFetchInterval = time.Millisecond
+}
+
+func TestMempoolWatcher(t *testing.T) {
+	owner := testWallet.Owner()
+	node := &testTxNode{
+		statuses: map[string]*schema.TxStatus{"a": nil, "b": nil, "c": nil},
+		anchors:  map[string]string{},
+		txs: map[string]*schema.Transaction{
+			"a": {ID: "a", Owner: owner, Tags: utils.TagsEncode([]schema.Tag{{Name: "App-Name", Value: "goar"}})},
+			"b": {ID: "b", Owner: owner, Tags: utils.TagsEncode([]schema.Tag{{Name: "App-Name", Value: "other"}})},
+			"c": {ID: "c", Owner: utils.Base64Encode([]byte("someone else")), Tags: utils.TagsEncode([]schema.Tag{{Name: "App-Name", Value: "goar"}})},
+			"d": {ID: "d", Owner: owner, Tags: utils.TagsEncode([]schema.Tag{{Name: "App-Name", Value: "goar"}})},
+		},
+	}
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	m := NewMempoolWatcher(NewClient(srv.URL), MempoolFilter{
+		Owners: []string{testWallet.Signer.Address},
+		Tags:   []schema.Tag{{Name: "App-Name", Value: "goar"}},
+	})
+	ch := m.Run(ctx)
+
+	ev := <-ch
+	assert.Equal(t, schema.MempoolEventEntered, ev.Type)
+	assert.Equal(t, "a", ev.TxId)
+	assert.Equal(t, owner, ev.Tx.Owner)
+
+	node.set(func() {
+		node.statuses["a"] = &schema.TxStatus{BlockHeight: 10, NumberOfConfirmations: 1}
+		node.statuses["d"] = nil
+	})
+	events := map[string]schema.MempoolEvent{}
+	for len(events) < 2 {
+		ev = <-ch
+		events[ev.TxId] = ev
+	}
+	assert.Equal(t, schema.MempoolEventMined, events["a"].Type)
+	assert.Equal(t, 10, events["a"].Status.BlockHeight)
+	assert.Equal(t, schema.MempoolEventEntered, events["d"].Type)
+
+	node.set(func() { delete(node.statuses, "d") })
+	ev = <-ch
+	assert.Equal(t, schema.MempoolEventDropped, ev.Type)
+	assert.Equal(t, "d", ev.TxId)
+	assert.NotNil(t, ev.Tx)
+}
+
+func TestMempoolWatcher_Cancel(t *testing.T) {
+	defer func(interval time.Duration) { mempoolFetchInterval = interval }(mempoolFetchInterval)
+	mempoolFetchInterval = time.Minute
+	node := &testTxNode{statuses: map[string]*schema.TxStatus{}, anchors: map[string]string{}}
+	for i := 0; i < 100; i++ {
+		node.statuses[fmt.Sprintf("tx%d", i)] = nil
+	}
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+
+	// cancelled while waiting between two unconfirmed tx requests of the first snapshot
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	ch := NewMempoolWatcher(NewClient(srv.URL), MempoolFilter{}).Run(ctx)
+	events := 0
+	done := make(chan struct{})
+	go func() {
+		for range ch {
+			events++
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("watcher not stopped")
+	}
+	assert.Equal(t, 1, events)
+}
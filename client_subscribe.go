@@ -0,0 +1,158 @@
+package goar
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/permadao/goar/schema"
+)
+
+var blockPollInterval = 30 * time.Second
+
+// SubscribeBlocks emits the blocks from fromHeight on, in order, then follows the chain tip.
+// Missed heights are backfilled and forks are reported with a schema.BlockEventReorg event,
+// which carries the blocks rolled back and the new branch replacing them.
+// fromHeight < 0 starts from the current tip. The channel is closed when ctx is done.
+func (c *Client) SubscribeBlocks(ctx context.Context, fromHeight int64) (<-chan schema.BlockEvent, error) {
+	info, err := c.GetInfo()
+	if err != nil {
+		return nil, err
+	}
+	if fromHeight < 0 || fromHeight > info.Height {
+		fromHeight = info.Height
+	}
+
+	ch := make(chan schema.BlockEvent)
+	s := &blockSubscriber{client: c, ch: ch, next: fromHeight}
+	go func() {
+		defer close(ch)
+		for {
+			if err := s.poll(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Warn("subscribe blocks poll failed", "err", err, "next", s.next)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(blockPollInterval):
+			}
+		}
+	}()
+	return ch, nil
+}
+
+type blockSubscriber struct {
+	client *Client
+	ch     chan schema.BlockEvent
+	next   int64
+	recent []*schema.Block // tracked chain, lowest height first
+}
+
+func (s *blockSubscriber) poll(ctx context.Context) error {
+	info, err := s.client.GetInfo()
+	if err != nil {
+		return err
+	}
+	for s.next <= info.Height {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		b, err := s.client.GetBlockByHeight(s.next)
+		if err != nil {
+			return err
+		}
+		if err = s.accept(ctx, b); err != nil {
+			return err
+		}
+	}
+
+	// the tip was replaced by a block of the same or a lower height
+	if len(s.recent) > 0 && info.Current != "" && s.indexOf(info.Current) < 0 {
+		b, err := s.client.GetBlockByID(info.Current)
+		if err != nil {
+			return err
+		}
+		return s.accept(ctx, b)
+	}
+	return nil
+}
+
+func (s *blockSubscriber) accept(ctx context.Context, b *schema.Block) error {
+	if s.indexOf(b.IndepHash) >= 0 {
+		return nil
+	}
+	if len(s.recent) == 0 || b.PreviousBlock == s.recent[len(s.recent)-1].IndepHash {
+		s.push(b)
+		return s.emit(ctx, schema.BlockEvent{Type: schema.BlockEventNew, Block: b})
+	}
+
+	// fork, walk the new branch back to a tracked block
+	branch := []*schema.Block{b}
+	forkIdx, found := s.forkPoint(b.PreviousBlock)
+	for !found {
+		if len(branch) > schema.BLOCK_TRACK_DEPTH {
+			return fmt.Errorf("reorg deeper than %d blocks at height %d", schema.BLOCK_TRACK_DEPTH, b.Height)
+		}
+		prev, err := s.client.GetBlockByID(branch[0].PreviousBlock)
+		if err != nil {
+			return err
+		}
+		branch = append([]*schema.Block{prev}, branch...)
+		forkIdx, found = s.forkPoint(prev.PreviousBlock)
+	}
+	rolledBack := make([]*schema.Block, 0, len(s.recent)-forkIdx-1)
+	for i := len(s.recent) - 1; i > forkIdx; i-- {
+		rolledBack = append(rolledBack, s.recent[i])
+	}
+	s.recent = s.recent[:forkIdx+1]
+	for _, nb := range branch {
+		s.push(nb)
+	}
+	return s.emit(ctx, schema.BlockEvent{
+		Type:       schema.BlockEventReorg,
+		Block:      b,
+		RolledBack: rolledBack,
+		Replaced:   branch,
+	})
+}
+
+func (s *blockSubscriber) push(b *schema.Block) {
+	s.recent = append(s.recent, b)
+	if len(s.recent) > schema.BLOCK_TRACK_DEPTH {
+		s.recent = s.recent[len(s.recent)-schema.BLOCK_TRACK_DEPTH:]
+	}
+	s.next = b.Height + 1
+}
+
+// forkPoint index of the tracked block a branch built on previousBlock forks from,
+// -1 when it forks from the parent of the oldest tracked block and the whole window is rolled back
+func (s *blockSubscriber) forkPoint(previousBlock string) (int, bool) {
+	if idx := s.indexOf(previousBlock); idx >= 0 {
+		return idx, true
+	}
+	if len(s.recent) > 0 && previousBlock != "" && previousBlock == s.recent[0].PreviousBlock {
+		return -1, true
+	}
+	return -1, false
+}
+
+func (s *blockSubscriber) indexOf(indepHash string) int {
+	for i := len(s.recent) - 1; i >= 0; i-- {
+		if s.recent[i].IndepHash == indepHash {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *blockSubscriber) emit(ctx context.Context, event schema.BlockEvent) error {
+	select {
+	case s.ch <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,187 @@
+package goar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/permadao/goar/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// testChain serves /info, /block/height and /block/hash of a chain that can be forked
+type testChain struct {
+	lock   sync.Mutex
+	chain  []*schema.Block
+	byHash map[string]*schema.Block
+}
+
+func newTestChain(n int) *testChain {
+	tc := &testChain{byHash: map[string]*schema.Block{}}
+	tc.extend(n, "a")
+	return tc
+}
+
+func (tc *testChain) extend(n int, branch string) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	for i := 0; i < n; i++ {
+		b := &schema.Block{Height: int64(len(tc.chain)), IndepHash: fmt.Sprintf("%s-%d", branch, len(tc.chain))}
+		if len(tc.chain) > 0 {
+			b.PreviousBlock = tc.chain[len(tc.chain)-1].IndepHash
+		}
+		tc.chain = append(tc.chain, b)
+		tc.byHash[b.IndepHash] = b
+	}
+}
+
+// fork drops the blocks from height on and builds n new blocks on another branch
+func (tc *testChain) fork(height int64, n int, branch string) {
+	tc.lock.Lock()
+	tc.chain = tc.chain[:height]
+	tc.lock.Unlock()
+	tc.extend(n, branch)
+}
+
+func (tc *testChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	var res interface{}
+	switch {
+	case r.URL.Path == "/info":
+		tip := tc.chain[len(tc.chain)-1]
+		res = schema.NetworkInfo{Height: tip.Height, Current: tip.IndepHash}
+	case strings.HasPrefix(r.URL.Path, "/block/height/"):
+		h, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/block/height/"))
+		if h >= len(tc.chain) {
+			w.WriteHeader(404)
+			return
+		}
+		res = tc.chain[h]
+	case strings.HasPrefix(r.URL.Path, "/block/hash/"):
+		b, ok := tc.byHash[strings.TrimPrefix(r.URL.Path, "/block/hash/")]
+		if !ok {
+			w.WriteHeader(404)
+			return
+		}
+		res = b
+	default:
+		w.WriteHeader(404)
+		return
+	}
+	json.NewEncoder(w).Encode(res)
+}
+
+func nextBlockEvent(t *testing.T, ch <-chan schema.BlockEvent) schema.BlockEvent {
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("no block event")
+	}
+	return schema.BlockEvent{}
+}
+
+func TestClient_SubscribeBlocks(t *testing.T) {
+	blockPollInterval = 10 * time.Millisecond
+	tc := newTestChain(5)
+	srv := httptest.NewServer(tc)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := NewClient(srv.URL).SubscribeBlocks(ctx, 2)
+	assert.NoError(t, err)
+
+	// backfill
+	for h := int64(2); h < 5; h++ {
+		e := nextBlockEvent(t, ch)
+		assert.Equal(t, schema.BlockEventNew, e.Type)
+		assert.Equal(t, h, e.Block.Height)
+	}
+
+	tc.extend(1, "a")
+	e := nextBlockEvent(t, ch)
+	assert.Equal(t, schema.BlockEventNew, e.Type)
+	assert.Equal(t, "a-5", e.Block.IndepHash)
+
+	// replace a-4 and a-5 by b-4, b-5 and b-6
+	tc.fork(4, 3, "b")
+	e = nextBlockEvent(t, ch)
+	assert.Equal(t, schema.BlockEventReorg, e.Type)
+	assert.Equal(t, 2, len(e.RolledBack))
+	assert.Equal(t, "a-5", e.RolledBack[0].IndepHash)
+	assert.Equal(t, "a-4", e.RolledBack[1].IndepHash)
+	assert.Equal(t, 3, len(e.Replaced))
+	assert.Equal(t, "b-4", e.Replaced[0].IndepHash)
+	assert.Equal(t, "b-6", e.Block.IndepHash)
+
+	// tip replaced at the same height
+	tc.fork(6, 1, "c")
+	e = nextBlockEvent(t, ch)
+	assert.Equal(t, schema.BlockEventReorg, e.Type)
+	assert.Equal(t, "b-6", e.RolledBack[0].IndepHash)
+	assert.Equal(t, "c-6", e.Block.IndepHash)
+
+	tc.extend(1, "c")
+	e = nextBlockEvent(t, ch)
+	assert.Equal(t, schema.BlockEventNew, e.Type)
+	assert.Equal(t, "c-7", e.Block.IndepHash)
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestClient_SubscribeBlocks_ForkFirstBlock(t *testing.T) {
+	blockPollInterval = 10 * time.Millisecond
+	tc := newTestChain(5)
+	srv := httptest.NewServer(tc)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := NewClient(srv.URL).SubscribeBlocks(ctx, -1)
+	assert.NoError(t, err)
+	e := nextBlockEvent(t, ch)
+	assert.Equal(t, "a-4", e.Block.IndepHash)
+
+	// the starting tip, the only tracked block, is orphaned by a sibling
+	tc.fork(4, 1, "b")
+	e = nextBlockEvent(t, ch)
+	assert.Equal(t, schema.BlockEventReorg, e.Type)
+	assert.Equal(t, 1, len(e.RolledBack))
+	assert.Equal(t, "a-4", e.RolledBack[0].IndepHash)
+	assert.Equal(t, 1, len(e.Replaced))
+	assert.Equal(t, "b-4", e.Block.IndepHash)
+
+	// the first tracked block is orphaned by a longer branch
+	tc.extend(1, "b")
+	e = nextBlockEvent(t, ch)
+	assert.Equal(t, "b-5", e.Block.IndepHash)
+	tc.fork(4, 3, "c")
+	e = nextBlockEvent(t, ch)
+	assert.Equal(t, schema.BlockEventReorg, e.Type)
+	assert.Equal(t, 2, len(e.RolledBack))
+	assert.Equal(t, "b-5", e.RolledBack[0].IndepHash)
+	assert.Equal(t, "b-4", e.RolledBack[1].IndepHash)
+	assert.Equal(t, 3, len(e.Replaced))
+	assert.Equal(t, "c-4", e.Replaced[0].IndepHash)
+	assert.Equal(t, "c-6", e.Block.IndepHash)
+
+	tc.extend(1, "c")
+	e = nextBlockEvent(t, ch)
+	assert.Equal(t, schema.BlockEventNew, e.Type)
+	assert.Equal(t, "c-7", e.Block.IndepHash)
+
+	cancel()
+	for range ch {
+	}
+}
@@ -1,6 +1,7 @@
 package goar
 
 import (
+	"context"
 	"testing"
 
 	"github.com/permadao/goar/schema"
@@ -363,3 +364,22 @@ func TestNewClient2(t *testing.T) {
 	}
 	t.Log(len(bundleItems))
 }
+
+func TestClient_VerifyTxInclusion(t *testing.T) {
+	c := NewClient("https://arweave.net")
+	status, err := c.VerifyTxInclusion(context.Background(), "x-q8ibbTfXIcdDXqQ3xaPD3PuShj832G_xzNT5QrVjY")
+	assert.NoError(t, err)
+	t.Log(status.BlockHeight, status.BlockIndepHash)
+}
+
+func TestClient_VerifyBlockRange(t *testing.T) {
+	c := NewClient("https://arweave.net")
+	divergence, err := c.VerifyBlockRange(context.Background(), 1095730, 1095750)
+	assert.NoError(t, err)
+	assert.Nil(t, divergence)
+
+	// across the 2.6 fork and a batch boundary, with signed blocks
+	divergence, err = c.VerifyBlockRange(context.Background(), 1132150, 1132260)
+	assert.NoError(t, err)
+	assert.Nil(t, divergence)
+}
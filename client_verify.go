@@ -0,0 +1,249 @@
+package goar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/panjf2000/ants/v2"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+)
+
+var (
+	// retries of a block request hitting the gateway request limit
+	blockRetryMax      = 5
+	blockRetryInterval = time.Second
+)
+
+// VerifyTxInclusion proves that a mined transaction is part of the block reported by /tx/{id}/status.
+// The block is verified with its indep_hash and signature, the tx header with its id and signature, then
+// the tx data_root and size are checked against the block tx_root with the tx_path served by /chunk.
+// Transactions without data are proven by the txs list of the verified block.
+// schema.ErrUnverifiable is returned when the node does not serve the tx_path,
+// schema.ErrUnsupportedBlockVersion when the block can not be verified.
+func (c *Client) VerifyTxInclusion(ctx context.Context, txID string) (*schema.TxStatus, error) {
+	status, err := c.GetTransactionStatus(txID)
+	if err != nil {
+		return nil, err
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	block, err := c.GetBlockByID(status.BlockIndepHash)
+	if err != nil {
+		return nil, err
+	}
+	if block.IndepHash != status.BlockIndepHash || block.Height != int64(status.BlockHeight) {
+		return nil, fmt.Errorf("block mismatch, status: %d %s, block: %d %s", status.BlockHeight, status.BlockIndepHash, block.Height, block.IndepHash)
+	}
+	if err = utils.VerifyIndepHash(*block); err != nil {
+		return nil, fmt.Errorf("verify block %s failed: %w", block.IndepHash, err)
+	}
+	if block.Signature != "" {
+		if err = utils.VerifyBlockSignature(*block); err != nil {
+			return nil, fmt.Errorf("verify block %s signature failed: %w", block.IndepHash, err)
+		}
+	}
+	if !utils.ContainsInSlice(block.Txs, txID) {
+		return nil, fmt.Errorf("tx %s not listed in block %s", txID, block.IndepHash)
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	tx, err := c.GetTransactionByID(txID)
+	if err != nil {
+		return nil, err
+	}
+	if tx.ID != txID {
+		return nil, fmt.Errorf("got tx %s instead of %s", tx.ID, txID)
+	}
+	if err = utils.VerifyTransaction(*tx); err != nil {
+		return nil, fmt.Errorf("verify tx %s failed: %w", txID, err)
+	}
+	dataSize, err := strconv.ParseInt(tx.DataSize, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if dataSize == 0 {
+		return status, nil
+	}
+
+	err = c.verifyTxPath(block, tx, dataSize)
+	if err == schema.ErrNotFound {
+		return nil, fmt.Errorf("%w: tx_path of tx %s", schema.ErrUnverifiable, txID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (c *Client) verifyTxPath(block *schema.Block, tx *schema.Transaction, dataSize int64) error {
+	if block.WeaveSize == nil || block.BlockSize == nil {
+		return errors.New("block weave_size and block_size are required")
+	}
+	weaveSize := block.WeaveSize.Int64()
+	blockSize := block.BlockSize.Int64()
+	offset, err := c.getTransactionOffset(tx.ID)
+	if err != nil {
+		return err
+	}
+	endOffset, err := strconv.ParseInt(offset.Offset, 10, 64)
+	if err != nil {
+		return err
+	}
+	blockStart := weaveSize - blockSize
+	if endOffset < blockStart || endOffset >= weaveSize {
+		return fmt.Errorf("tx offset %d out of block range [%d, %d)", endOffset, blockStart, weaveSize)
+	}
+
+	chunk, err := c.getChunk(endOffset)
+	if err != nil {
+		return err
+	}
+	txPath, err := utils.Base64Decode(chunk.TxPath)
+	if err != nil {
+		return err
+	}
+	txRoot, err := utils.Base64Decode(block.TxRoot)
+	if err != nil {
+		return err
+	}
+	res, err := utils.VerifyTxPath(txRoot, int(blockSize), int(endOffset-blockStart), txPath)
+	if err != nil {
+		return err
+	}
+	if utils.Base64Encode(res.DataHash) != tx.DataRoot {
+		return fmt.Errorf("data_root mismatch, tx_path: %s, tx: %s", utils.Base64Encode(res.DataHash), tx.DataRoot)
+	}
+	if int64(res.ChunkSize) != dataSize {
+		return fmt.Errorf("data_size mismatch, tx_path: %d, tx: %d", res.ChunkSize, dataSize)
+	}
+	if int64(res.RightBound) != endOffset-blockStart+1 {
+		return errors.New("tx end offset does not match tx_path")
+	}
+	return nil
+}
+
+// VerifyBlockRange fetches the block headers from height `from` to `to` concurrently and checks that
+// every indep_hash and miner signature is correct, every block links to the previous one and the node hash_list agrees.
+// The range is verified by batches of schema.BLOCK_VERIFY_BATCH_SIZE blocks, requests hitting the gateway
+// request limit are retried with backoff.
+// It returns the first divergence found, nil means the whole range verified.
+// Blocks of arweave 1.0 and 2.8+ can not be verified, schema.ErrUnsupportedBlockVersion is returned for them.
+func (c *Client) VerifyBlockRange(ctx context.Context, from, to int64) (*schema.BlockDivergence, error) {
+	if from > to {
+		return nil, errors.New("from must <= to")
+	}
+	var prev *schema.Block
+	for start := from; start <= to; start += schema.BLOCK_VERIFY_BATCH_SIZE {
+		end := min(start+schema.BLOCK_VERIFY_BATCH_SIZE-1, to)
+		divergence, last, err := c.verifyBlockBatch(ctx, start, end, prev)
+		if err != nil || divergence != nil {
+			return divergence, err
+		}
+		prev = last
+	}
+	return nil, nil
+}
+
+// verifyBlockBatch verifies the blocks from `from` to `to`, the first one must link to prev when not nil.
+// It returns the last block of the batch.
+func (c *Client) verifyBlockBatch(ctx context.Context, from, to int64, prev *schema.Block) (*schema.BlockDivergence, *schema.Block, error) {
+	blocks := make([]*schema.Block, to-from+1)
+	errs := make([]error, to-from+1)
+	var wg sync.WaitGroup
+	p, err := ants.NewPoolWithFunc(schema.DEFAULT_BLOCK_CONCURRENT_NUM, func(i interface{}) {
+		defer wg.Done()
+		idx := i.(int64)
+		blocks[idx], errs[idx] = c.getBlockByHeightRetry(ctx, from+idx)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer p.Release()
+
+	for i := int64(0); i < int64(len(blocks)); i++ {
+		wg.Add(1)
+		if err = p.Invoke(i); err != nil {
+			wg.Done()
+			log.Error("p.Invoke(i)", "err", err, "i", i)
+			break
+		}
+	}
+	wg.Wait()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	for i, e := range errs {
+		if e != nil {
+			return nil, nil, fmt.Errorf("get block %d failed: %v", from+int64(i), e)
+		}
+	}
+
+	hashList, err := c.GetBlockHashList(int(from), int(to))
+	if err != nil {
+		return nil, nil, err
+	}
+	// the node serves the range as its block index, from the highest block to the lowest
+	if len(hashList) != len(blocks) {
+		return &schema.BlockDivergence{Height: to, IndepHash: blocks[len(blocks)-1].IndepHash, Reason: fmt.Sprintf("hash_list length %d, expected %d", len(hashList), len(blocks))}, nil, nil
+	}
+
+	for i, b := range blocks {
+		height := from + int64(i)
+		if b.Height != height {
+			return &schema.BlockDivergence{Height: height, IndepHash: b.IndepHash, Reason: fmt.Sprintf("got block of height %d", b.Height)}, nil, nil
+		}
+		if err := utils.VerifyIndepHash(*b); err != nil {
+			if errors.Is(err, schema.ErrUnsupportedBlockVersion) {
+				return nil, nil, err
+			}
+			return &schema.BlockDivergence{Height: height, IndepHash: b.IndepHash, Reason: err.Error()}, nil, nil
+		}
+		if b.Signature != "" {
+			if err := utils.VerifyBlockSignature(*b); err != nil {
+				return &schema.BlockDivergence{Height: height, IndepHash: b.IndepHash, Reason: fmt.Sprintf("invalid signature: %v", err)}, nil, nil
+			}
+		}
+		if i > 0 {
+			prev = blocks[i-1]
+		}
+		if prev != nil && b.PreviousBlock != prev.IndepHash {
+			return &schema.BlockDivergence{Height: height, IndepHash: b.IndepHash, Reason: fmt.Sprintf("previous_block %s does not link to %s", b.PreviousBlock, prev.IndepHash)}, nil, nil
+		}
+		if h := hashList[len(hashList)-1-i]; h != b.IndepHash {
+			return &schema.BlockDivergence{Height: height, IndepHash: b.IndepHash, Reason: fmt.Sprintf("node hash_list has %s at this height", h)}, nil, nil
+		}
+	}
+	return nil, blocks[len(blocks)-1], nil
+}
+
+// getBlockByHeightRetry retries on the gateway request limit, waiting blockRetryInterval doubled on each retry
+func (c *Client) getBlockByHeightRetry(ctx context.Context, height int64) (*schema.Block, error) {
+	interval := blockRetryInterval
+	for i := 0; ; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		b, err := c.GetBlockByHeight(height)
+		if err != schema.ErrRequestLimit || i >= blockRetryMax {
+			return b, err
+		}
+		log.Warn("get block request limit, retry", "height", height, "in", interval)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+	}
+}
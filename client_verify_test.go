@@ -0,0 +1,54 @@
+package goar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/permadao/goar/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetBlockByHeightRetry(t *testing.T) {
+	defer func(interval time.Duration) { blockRetryInterval = interval }(blockRetryInterval)
+	blockRetryInterval = 10 * time.Millisecond
+	var limited, calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.AddInt32(&limited, -1) >= 0 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(schema.Block{Height: 7, IndepHash: "b-7"})
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	atomic.StoreInt32(&limited, 2)
+	start := time.Now()
+	b, err := c.getBlockByHeightRetry(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "b-7", b.IndepHash)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	// waited 10ms then 20ms
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	// the limit persists
+	atomic.StoreInt32(&calls, 0)
+	atomic.StoreInt32(&limited, int32(blockRetryMax+1))
+	_, err = c.getBlockByHeightRetry(context.Background(), 7)
+	assert.Equal(t, schema.ErrRequestLimit, err)
+	assert.Equal(t, int32(blockRetryMax+1), atomic.LoadInt32(&calls))
+
+	// cancelled while waiting
+	atomic.StoreInt32(&limited, 1)
+	blockRetryInterval = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.getBlockByHeightRetry(ctx, 7)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -0,0 +1,158 @@
+package goar
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+)
+
+const defaultFeeCacheTTL = time.Minute
+
+// FeeEstimator quotes tx rewards in winston. Prices are cached per chunk-size bucket and target,
+// as the network prices data by 256 KiB chunks.
+type FeeEstimator struct {
+	client *Client
+
+	Multiplier *big.Rat // nil means 1, eg: big.NewRat(115, 100) pays 1.15 * price
+	Min        *big.Int // optional, estimates are raised to Min
+	Max        *big.Int // optional, estimates are lowered to Max, schema.ErrFeeExceedsMax when the price is higher
+	CacheTTL   time.Duration
+
+	lock  sync.Mutex
+	cache map[string]feeCacheEntry
+}
+
+// FeeQuote estimated tx reward
+type FeeQuote struct {
+	Reward Amount     `json:"reward"`
+	USD    *big.Float `json:"usd,omitempty"` // nil when the block has no valid usd_to_ar_rate
+}
+
+type feeCacheEntry struct {
+	value     *big.Int
+	rate      []string // usd_to_ar_rate
+	expiresAt time.Time
+}
+
+func NewFeeEstimator(client *Client) *FeeEstimator {
+	return &FeeEstimator{
+		client:   client,
+		CacheTTL: defaultFeeCacheTTL,
+		cache:    make(map[string]feeCacheEntry),
+	}
+}
+
+// Price network price of a tx, without multiplier and caps.
+// An empty target prices a data tx, otherwise the new wallet fee is included when the target is unseen.
+func (f *FeeEstimator) Price(dataSize int, target string) (*big.Int, error) {
+	if dataSize < 0 {
+		return nil, errors.New("data size must >= 0")
+	}
+	bucket := (dataSize + schema.MAX_CHUNK_SIZE - 1) / schema.MAX_CHUNK_SIZE
+	key := fmt.Sprintf("%d/%s", bucket, target)
+	if e, ok := f.cached(key); ok {
+		return new(big.Int).Set(e.value), nil
+	}
+
+	var t *string
+	if target != "" {
+		t = &target
+	}
+	price, err := f.client.GetTransactionPriceWinston(bucket*schema.MAX_CHUNK_SIZE, t)
+	if err != nil {
+		return nil, err
+	}
+	f.store(key, feeCacheEntry{value: price})
+	return new(big.Int).Set(price), nil
+}
+
+// Estimate reward to pay: the price scaled by Multiplier, within Min and Max
+func (f *FeeEstimator) Estimate(dataSize int, target string) (*big.Int, error) {
+	if f.Multiplier != nil && f.Multiplier.Sign() <= 0 {
+		return nil, errors.New("multiplier must > 0")
+	}
+	price, err := f.Price(dataSize, target)
+	if err != nil {
+		return nil, err
+	}
+	if f.Max != nil && price.Cmp(f.Max) > 0 {
+		return nil, schema.ErrFeeExceedsMax
+	}
+	reward := price
+	if f.Multiplier != nil {
+		reward = utils.MulReward(price, f.Multiplier)
+	}
+	if f.Min != nil && reward.Cmp(f.Min) < 0 {
+		reward = new(big.Int).Set(f.Min)
+	}
+	if f.Max != nil && reward.Cmp(f.Max) > 0 {
+		reward = new(big.Int).Set(f.Max)
+	}
+	return reward, nil
+}
+
+func (f *FeeEstimator) EstimateAmount(dataSize int, target string) (Amount, error) {
+	reward, err := f.Estimate(dataSize, target)
+	if err != nil {
+		return Amount{}, err
+	}
+	return Amount{w: reward}, nil
+}
+
+// Quote estimate as an Amount and in USD, the USD value uses usd_to_ar_rate of the current block
+// and is nil when the block has no valid rate
+func (f *FeeEstimator) Quote(dataSize int, target string) (*FeeQuote, error) {
+	reward, err := f.Estimate(dataSize, target)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := f.usdToArRate()
+	if err != nil {
+		return nil, err
+	}
+	return &FeeQuote{
+		Reward: Amount{w: reward},
+		USD:    utils.ARToUSD(utils.WinstonToAR(reward), rate),
+	}, nil
+}
+
+func (f *FeeEstimator) usdToArRate() ([]string, error) {
+	if e, ok := f.cached("usd_to_ar_rate"); ok {
+		return e.rate, nil
+	}
+	info, err := f.client.GetInfo()
+	if err != nil {
+		return nil, err
+	}
+	b, err := f.client.GetBlockByID(info.Current)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.UsdToArRate) != 2 {
+		log.Warn("block has no usd_to_ar_rate", "block", b.IndepHash)
+	}
+	f.store("usd_to_ar_rate", feeCacheEntry{rate: b.UsdToArRate})
+	return b.UsdToArRate, nil
+}
+
+func (f *FeeEstimator) cached(key string) (feeCacheEntry, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	e, ok := f.cache[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		return feeCacheEntry{}, false
+	}
+	return e, true
+}
+
+func (f *FeeEstimator) store(key string, e feeCacheEntry) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	e.expiresAt = time.Now().Add(f.CacheTTL)
+	f.cache[key] = e
+}
@@ -0,0 +1,97 @@
+package goar
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/permadao/goar/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeEstimator(t *testing.T) {
+	var priceCalls, noRate int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch parts[0] {
+		case "price":
+			atomic.AddInt32(&priceCalls, 1)
+			size, _ := strconv.Atoi(parts[1])
+			price := new(big.Int).Mul(big.NewInt(int64(size/schema.MAX_CHUNK_SIZE+1)), big.NewInt(1e15))
+			if len(parts) == 3 && parts[2] == "new-wallet" {
+				price.Add(price, big.NewInt(1e12))
+			}
+			fmt.Fprint(w, price.String())
+		case "info":
+			json.NewEncoder(w).Encode(schema.NetworkInfo{Height: 10, Current: "tip"})
+		case "block":
+			if atomic.LoadInt32(&noRate) == 1 {
+				json.NewEncoder(w).Encode(schema.Block{IndepHash: "tip"})
+				return
+			}
+			json.NewEncoder(w).Encode(schema.Block{IndepHash: "tip", UsdToArRate: []string{"1", "10"}})
+		}
+	}))
+	defer srv.Close()
+
+	f := NewFeeEstimator(NewClient(srv.URL))
+	p, err := f.Price(1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "2000000000000000", p.String())
+	// same chunk bucket, cached
+	p, err = f.Price(schema.MAX_CHUNK_SIZE, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "2000000000000000", p.String())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&priceCalls))
+
+	p, err = f.Price(0, "new-wallet")
+	assert.NoError(t, err)
+	assert.Equal(t, "1001000000000000", p.String())
+
+	f.Multiplier = big.NewRat(3, 2)
+	r, err := f.Estimate(1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "3000000000000000", r.String())
+
+	for _, m := range []*big.Rat{new(big.Rat), big.NewRat(-1, 2)} {
+		f.Multiplier = m
+		_, err = f.Estimate(1, "")
+		assert.EqualError(t, err, "multiplier must > 0")
+	}
+	f.Multiplier = big.NewRat(3, 2)
+
+	f.Max = big.NewInt(2500000000000000)
+	r, err = f.Estimate(1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "2500000000000000", r.String())
+	_, err = f.Estimate(schema.MAX_CHUNK_SIZE*2, "")
+	assert.Equal(t, schema.ErrFeeExceedsMax, err)
+
+	f.Max = nil
+	f.Multiplier = nil
+	f.Min = big.NewInt(5000000000000000)
+	r, err = f.Estimate(1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "5000000000000000", r.String())
+
+	f.Min = nil
+	q, err := f.Quote(1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "2000000000000000", q.Reward.Winston().String())
+	assert.Equal(t, "2000", q.Reward.AR())
+	assert.Equal(t, "20000", q.USD.Text('f', 0))
+
+	// no usd_to_ar_rate, the quote has no USD value
+	atomic.StoreInt32(&noRate, 1)
+	f = NewFeeEstimator(NewClient(srv.URL))
+	q, err = f.Quote(1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "2000000000000000", q.Reward.Winston().String())
+	assert.Nil(t, q.USD)
+}
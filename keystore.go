@@ -0,0 +1,229 @@
+package goar
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/everFinance/gojwk"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/permadao/goar/utils"
+)
+
+const (
+	keystoreVersion = 1
+	keystoreCipher  = "aes-256-gcm"
+	keystoreKeyLen  = 32
+	keystoreSaltLen = 32
+	// bound of the kdf memory read from a keystore file, 4 times the default scrypt cost
+	keystoreMaxKDFMemory = 1 << 30
+
+	KeystoreKDFScrypt   = "scrypt"
+	KeystoreKDFArgon2id = "argon2id"
+)
+
+// KeystoreKDF derives the encryption key from the passphrase, the salt is generated on encryption
+type KeystoreKDF struct {
+	Name string `json:"-"`
+	Salt string `json:"salt"`
+	// scrypt
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+	// argon2id, Memory in KiB
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+var (
+	// same cost as the ethereum keystore v3 standard scrypt
+	ScryptKDF   = KeystoreKDF{Name: KeystoreKDFScrypt, N: 1 << 18, R: 8, P: 1}
+	Argon2idKDF = KeystoreKDF{Name: KeystoreKDFArgon2id, Time: 3, Memory: 64 * 1024, Threads: 4}
+)
+
+// Keystore versioned envelope of an encrypted JWK, the address is authenticated with the ciphertext
+type Keystore struct {
+	Version int            `json:"version"`
+	Address string         `json:"address"`
+	Crypto  KeystoreCrypto `json:"crypto"`
+}
+
+type KeystoreCrypto struct {
+	Cipher     string      `json:"cipher"`
+	CipherText string      `json:"ciphertext"`
+	Nonce      string      `json:"nonce"`
+	KDF        string      `json:"kdf"`
+	KDFParams  KeystoreKDF `json:"kdfparams"`
+}
+
+// EncryptJWK encrypts an arweave keyfile with a scrypt derived key
+func EncryptJWK(jwk []byte, passphrase string) ([]byte, error) {
+	return EncryptJWKWithKDF(jwk, passphrase, ScryptKDF)
+}
+
+func EncryptJWKWithKDF(jwk []byte, passphrase string, kdf KeystoreKDF) ([]byte, error) {
+	signer, err := NewSigner(jwk)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, keystoreSaltLen)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+	kdf.Salt = utils.Base64Encode(salt)
+	key, err := kdf.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newKeystoreGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	ks := Keystore{
+		Version: keystoreVersion,
+		Address: signer.Address,
+		Crypto: KeystoreCrypto{
+			Cipher:    keystoreCipher,
+			Nonce:     utils.Base64Encode(nonce),
+			KDF:       kdf.Name,
+			KDFParams: kdf,
+		},
+	}
+	ks.Crypto.CipherText = utils.Base64Encode(gcm.Seal(nil, nonce, jwk, []byte(ks.Address)))
+	return json.MarshalIndent(ks, "", "  ")
+}
+
+// DecryptJWK returns the keyfile of a keystore, the passphrase is wrong when the decryption fails
+func DecryptJWK(keystore []byte, passphrase string) ([]byte, error) {
+	ks := Keystore{}
+	if err := json.Unmarshal(keystore, &ks); err != nil {
+		return nil, err
+	}
+	if ks.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version: %d", ks.Version)
+	}
+	if ks.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unsupported keystore cipher: %s", ks.Crypto.Cipher)
+	}
+	kdf := ks.Crypto.KDFParams
+	kdf.Name = ks.Crypto.KDF
+	key, err := kdf.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newKeystoreGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := utils.Base64Decode(ks.Crypto.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid keystore nonce")
+	}
+	cipherText, err := utils.Base64Decode(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+	jwk, err := gcm.Open(nil, nonce, cipherText, []byte(ks.Address))
+	if err != nil {
+		return nil, errors.New("keystore decryption failed, wrong passphrase or corrupted keystore")
+	}
+
+	key2, err := gojwk.Unmarshal(jwk)
+	if err != nil {
+		return nil, err
+	}
+	if addr, err := utils.OwnerToAddress(key2.N); err != nil || addr != ks.Address {
+		return nil, errors.New("keystore address does not match the key")
+	}
+	return jwk, nil
+}
+
+// ChangeKeystorePassphrase re-encrypts the keystore with a new passphrase, with the same KDF settings and a new salt
+func ChangeKeystorePassphrase(keystore []byte, oldPassphrase, newPassphrase string) ([]byte, error) {
+	jwk, err := DecryptJWK(keystore, oldPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	ks := Keystore{}
+	if err = json.Unmarshal(keystore, &ks); err != nil {
+		return nil, err
+	}
+	kdf := ks.Crypto.KDFParams
+	kdf.Name = ks.Crypto.KDF
+	return EncryptJWKWithKDF(jwk, newPassphrase, kdf)
+}
+
+func NewSignerFromKeystore(path, passphrase string) (*Signer, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	jwk, err := DecryptJWK(b, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(jwk)
+}
+
+// proxyUrl: option
+func NewWalletFromKeystore(path, passphrase string, clientUrl string, proxyUrl ...string) (*Wallet, error) {
+	signer, err := NewSignerFromKeystore(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewWalletWithSigner(signer, clientUrl, proxyUrl...), nil
+}
+
+func (kdf KeystoreKDF) deriveKey(passphrase string) ([]byte, error) {
+	salt, err := utils.Base64Decode(kdf.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(salt) < 16 {
+		return nil, errors.New("keystore salt too short")
+	}
+	switch kdf.Name {
+	case KeystoreKDFScrypt:
+		if kdf.N <= 1 || kdf.R <= 0 || kdf.P <= 0 {
+			return nil, errors.New("invalid keystore scrypt parameters")
+		}
+		// 128*N*r bytes of memory, run p times
+		if uint64(kdf.N) > keystoreMaxKDFMemory/128/uint64(kdf.R)/uint64(kdf.P) {
+			return nil, errors.New("keystore scrypt parameters too high")
+		}
+		return scrypt.Key([]byte(passphrase), salt, kdf.N, kdf.R, kdf.P, keystoreKeyLen)
+	case KeystoreKDFArgon2id:
+		if kdf.Time == 0 || kdf.Memory == 0 || kdf.Threads == 0 {
+			return nil, errors.New("invalid keystore argon2id parameters")
+		}
+		if kdf.Time > 100 || uint64(kdf.Memory)*1024 > keystoreMaxKDFMemory {
+			return nil, errors.New("keystore argon2id parameters too high")
+		}
+		return argon2.IDKey([]byte(passphrase), salt, kdf.Time, kdf.Memory, kdf.Threads, keystoreKeyLen), nil
+	default:
+		return nil, fmt.Errorf("unsupported keystore kdf: %s", kdf.Name)
+	}
+}
+
+func newKeystoreGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
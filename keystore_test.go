@@ -0,0 +1,75 @@
+package goar
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeystore(t *testing.T) {
+	jwk, err := os.ReadFile("testKey.json")
+	assert.NoError(t, err)
+	// cheap parameters for the test
+	kdfs := []KeystoreKDF{
+		{Name: KeystoreKDFScrypt, N: 1 << 10, R: 8, P: 1},
+		{Name: KeystoreKDFArgon2id, Time: 1, Memory: 1024, Threads: 1},
+	}
+	for _, kdf := range kdfs {
+		ks, err := EncryptJWKWithKDF(jwk, "passphrase", kdf)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(ks), `"d"`)
+
+		_, err = DecryptJWK(ks, "wrong")
+		assert.Error(t, err)
+		dec, err := DecryptJWK(ks, "passphrase")
+		assert.NoError(t, err)
+		assert.Equal(t, jwk, dec)
+
+		rotated, err := ChangeKeystorePassphrase(ks, "passphrase", "new passphrase")
+		assert.NoError(t, err)
+		_, err = DecryptJWK(rotated, "passphrase")
+		assert.Error(t, err)
+
+		path := filepath.Join(t.TempDir(), "keystore.json")
+		assert.NoError(t, os.WriteFile(path, rotated, 0600))
+		signer, err := NewSignerFromKeystore(path, "new passphrase")
+		assert.NoError(t, err)
+		assert.Equal(t, testWallet.Signer.Address, signer.Address)
+	}
+}
+
+func TestKeystore_Tampered(t *testing.T) {
+	jwk, err := os.ReadFile("testKey.json")
+	assert.NoError(t, err)
+	ks, err := EncryptJWKWithKDF(jwk, "passphrase", KeystoreKDF{Name: KeystoreKDFScrypt, N: 1 << 10, R: 8, P: 1})
+	assert.NoError(t, err)
+
+	// the address is authenticated
+	signer, err := GenerateWallet()
+	assert.NoError(t, err)
+	tampered := []byte(strings.Replace(string(ks), testWallet.Signer.Address, signer.Address, 1))
+	_, err = DecryptJWK(tampered, "passphrase")
+	assert.Error(t, err)
+
+	_, err = DecryptJWK([]byte(strings.Replace(string(ks), `"version": 1`, `"version": 2`, 1)), "passphrase")
+	assert.ErrorContains(t, err, "version")
+}
+
+func TestKeystoreKDF_Bounds(t *testing.T) {
+	salt := utils.Base64Encode(make([]byte, 32))
+	for _, kdf := range []KeystoreKDF{
+		{Name: KeystoreKDFScrypt, N: 1 << 22, R: 32, P: 16},
+		{Name: KeystoreKDFScrypt, N: 1 << 21, R: 8, P: 1},
+		{Name: KeystoreKDFScrypt, N: 1 << 18, R: 8, P: 8},
+		{Name: KeystoreKDFScrypt, N: 1 << 10, R: -8, P: 1},
+		{Name: KeystoreKDFArgon2id, Time: 3, Memory: 2 * 1024 * 1024, Threads: 4},
+	} {
+		kdf.Salt = salt
+		_, err := kdf.deriveKey("passphrase")
+		assert.Error(t, err, "%+v", kdf)
+	}
+}
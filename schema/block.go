@@ -1,32 +1,203 @@
 package schema
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
 type Block struct {
 	Nonce                    string        `json:"nonce"`
 	PreviousBlock            string        `json:"previous_block"`
 	Timestamp                int64         `json:"timestamp"`
 	LastRetarget             int64         `json:"last_retarget"`
-	Diff                     interface{}   `json:"diff"`
+	Diff                     *big.Int      `json:"diff"`
 	Height                   int64         `json:"height"`
 	Hash                     string        `json:"hash"`
 	IndepHash                string        `json:"indep_hash"`
 	Txs                      []string      `json:"txs"`
 	TxRoot                   string        `json:"tx_root"`
-	TxTree                   interface{}   `json:"tx_tree"`
-	HashList                 interface{}   `json:"hash_list"`
+	TxTree                   []string      `json:"tx_tree"`
+	HashList                 []string      `json:"hash_list,omitempty"`
 	HashListMerkle           string        `json:"hash_list_merkle"`
 	WalletList               string        `json:"wallet_list"`
 	RewardAddr               string        `json:"reward_addr"`
 	Tags                     []interface{} `json:"tags"`
-	RewardPool               interface{}   `json:"reward_pool"` // always string
-	WeaveSize                interface{}   `json:"weave_size"`  // always string
-	BlockSize                interface{}   `json:"block_size"`  // always string
-	CumulativeDiff           interface{}   `json:"cumulative_diff"`
+	RewardPool               *big.Int      `json:"reward_pool"`
+	WeaveSize                *big.Int      `json:"weave_size"`
+	BlockSize                *big.Int      `json:"block_size"`
+	CumulativeDiff           *big.Int      `json:"cumulative_diff"`
 	SizeTaggedTxs            interface{}   `json:"size_tagged_txs"`
 	Poa                      POA           `json:"poa"`
 	UsdToArRate              []string      `json:"usd_to_ar_rate"`
 	ScheduledUsdToArRate     []string      `json:"scheduled_usd_to_ar_rate"`
 	Packing25Threshold       string        `json:"packing_2_5_threshold"`
 	StrictDataSplitThreshold string        `json:"strict_data_split_threshold"`
+
+	// since arweave 2.6
+	HashPreimage                  string              `json:"hash_preimage,omitempty"`
+	RecallByte                    string              `json:"recall_byte,omitempty"`
+	Reward                        string              `json:"reward,omitempty"`
+	PreviousSolutionHash          string              `json:"previous_solution_hash,omitempty"`
+	PartitionNumber               int64               `json:"partition_number,omitempty"`
+	NonceLimiterInfo              *NonceLimiterInfo   `json:"nonce_limiter_info,omitempty"`
+	Poa2                          *POA                `json:"poa2,omitempty"`
+	RecallByte2                   string              `json:"recall_byte2,omitempty"`
+	Signature                     string              `json:"signature,omitempty"`
+	RewardKey                     string              `json:"reward_key,omitempty"` // miner public key modulus
+	PricePerGibMinute             string              `json:"price_per_gib_minute,omitempty"`
+	ScheduledPricePerGibMinute    string              `json:"scheduled_price_per_gib_minute,omitempty"`
+	RewardHistoryHash             string              `json:"reward_history_hash,omitempty"`
+	DebtSupply                    string              `json:"debt_supply,omitempty"`
+	KryderPlusRateMultiplier      string              `json:"kryder_plus_rate_multiplier,omitempty"`
+	KryderPlusRateMultiplierLatch string              `json:"kryder_plus_rate_multiplier_latch,omitempty"`
+	Denomination                  string              `json:"denomination,omitempty"`
+	RedenominationHeight          int64               `json:"redenomination_height,omitempty"`
+	DoubleSigningProof            *DoubleSigningProof `json:"double_signing_proof,omitempty"`
+	PreviousCumulativeDiff        string              `json:"previous_cumulative_diff,omitempty"`
+
+	// since arweave 2.7
+	MerkleRebaseSupportThreshold string `json:"merkle_rebase_support_threshold,omitempty"`
+	ChunkHash                    string `json:"chunk_hash,omitempty"`
+	Chunk2Hash                   string `json:"chunk2_hash,omitempty"`
+	BlockTimeHistoryHash         string `json:"block_time_history_hash,omitempty"`
+
+	// big integer fields the node sent as JSON numbers, the others are sent as strings
+	numberFields map[string]bool
+}
+
+// blockBigIntFields json names of the *big.Int fields of Block
+var blockBigIntFields = []string{"diff", "reward_pool", "weave_size", "block_size", "cumulative_diff"}
+
+func (b *Block) bigIntField(name string) **big.Int {
+	switch name {
+	case "diff":
+		return &b.Diff
+	case "reward_pool":
+		return &b.RewardPool
+	case "weave_size":
+		return &b.WeaveSize
+	case "block_size":
+		return &b.BlockSize
+	case "cumulative_diff":
+		return &b.CumulativeDiff
+	}
+	return nil
+}
+
+type blockAlias Block
+
+// UnmarshalJSON decodes the big integer fields whether the node sent them as strings or numbers,
+// the representation is kept so MarshalJSON produces the same JSON.
+func (b *Block) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*blockAlias
+		Diff           json.RawMessage `json:"diff"`
+		RewardPool     json.RawMessage `json:"reward_pool"`
+		WeaveSize      json.RawMessage `json:"weave_size"`
+		BlockSize      json.RawMessage `json:"block_size"`
+		CumulativeDiff json.RawMessage `json:"cumulative_diff"`
+	}{blockAlias: (*blockAlias)(b)}
+
+	// json unmarshal exist number precision problem
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&aux); err != nil {
+		return err
+	}
+
+	raws := map[string]json.RawMessage{
+		"diff":            aux.Diff,
+		"reward_pool":     aux.RewardPool,
+		"weave_size":      aux.WeaveSize,
+		"block_size":      aux.BlockSize,
+		"cumulative_diff": aux.CumulativeDiff,
+	}
+	b.numberFields = make(map[string]bool)
+	for _, name := range blockBigIntFields {
+		raw := raws[name]
+		field := b.bigIntField(name)
+		if len(raw) == 0 || string(raw) == "null" {
+			*field = nil
+			continue
+		}
+		str := string(raw)
+		if raw[0] == '"' {
+			if err := json.Unmarshal(raw, &str); err != nil {
+				return err
+			}
+		} else {
+			b.numberFields[name] = true
+		}
+		n, ok := new(big.Int).SetString(str, 10)
+		if !ok {
+			return fmt.Errorf("invalid %s: %s", name, str)
+		}
+		*field = n
+	}
+	return nil
+}
+
+// MarshalJSON encodes the big integer fields as strings unless they were decoded from numbers.
+func (b Block) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		blockAlias
+		Diff           json.RawMessage `json:"diff"`
+		RewardPool     json.RawMessage `json:"reward_pool"`
+		WeaveSize      json.RawMessage `json:"weave_size"`
+		BlockSize      json.RawMessage `json:"block_size"`
+		CumulativeDiff json.RawMessage `json:"cumulative_diff"`
+	}{blockAlias: blockAlias(b)}
+
+	raws := map[string]*json.RawMessage{
+		"diff":            &aux.Diff,
+		"reward_pool":     &aux.RewardPool,
+		"weave_size":      &aux.WeaveSize,
+		"block_size":      &aux.BlockSize,
+		"cumulative_diff": &aux.CumulativeDiff,
+	}
+	for _, name := range blockBigIntFields {
+		n := *b.bigIntField(name)
+		switch {
+		case n == nil:
+			*raws[name] = json.RawMessage("null")
+		case b.numberFields[name]:
+			*raws[name] = json.RawMessage(n.String())
+		default:
+			*raws[name] = json.RawMessage(strconv.Quote(n.String()))
+		}
+	}
+	return json.Marshal(aux)
+}
+
+// NonceLimiterInfo VDF state of a block, since arweave 2.6
+type NonceLimiterInfo struct {
+	Output              string   `json:"output"`
+	GlobalStepNumber    int64    `json:"global_step_number"`
+	Seed                string   `json:"seed"`
+	NextSeed            string   `json:"next_seed"`
+	ZoneUpperBound      int64    `json:"zone_upper_bound"`
+	NextZoneUpperBound  int64    `json:"next_zone_upper_bound"`
+	PrevOutput          string   `json:"prev_output"`
+	LastStepCheckpoints []string `json:"last_step_checkpoints"`
+	Checkpoints         []string `json:"checkpoints"`
+	VdfDifficulty       string   `json:"vdf_difficulty,omitempty"`      // since arweave 2.7
+	NextVdfDifficulty   string   `json:"next_vdf_difficulty,omitempty"` // since arweave 2.7
+}
+
+// DoubleSigningProof proof that a miner signed two blocks of the same height, empty when not present
+type DoubleSigningProof struct {
+	PubKey     string `json:"pub_key,omitempty"`
+	Sig1       string `json:"sig1,omitempty"`
+	Cdiff1     string `json:"cdiff1,omitempty"`
+	PrevCdiff1 string `json:"prev_cdiff1,omitempty"`
+	Preimage1  string `json:"preimage1,omitempty"`
+	Sig2       string `json:"sig2,omitempty"`
+	Cdiff2     string `json:"cdiff2,omitempty"`
+	PrevCdiff2 string `json:"prev_cdiff2,omitempty"`
+	Preimage2  string `json:"preimage2,omitempty"`
 }
 
 type POA struct {
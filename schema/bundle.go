@@ -9,6 +9,12 @@ const (
 	ED25519SignType  = 2
 	EthereumSignType = 3
 	SolanaSignType   = 4
+	AptosSignType    = 5
+	// MultiAptosSignType owner is 32 ed25519 keys padded with zeros and the threshold,
+	// the signature is 32 slots of 64 bytes, the signature of key i in slot i, and the 4 bytes bitmap of the signing keys
+	MultiAptosSignType = 6
+	// TypedEthereumSignType EIP-712 signature, the owner is the lowercase 0x address
+	TypedEthereumSignType = 7
 )
 
 type SigMeta struct {
@@ -38,6 +44,21 @@ var SigConfigMap = map[int]SigMeta{
 		PubLength: 32,
 		SigName:   "solana",
 	},
+	AptosSignType: {
+		SigLength: 64,
+		PubLength: 32,
+		SigName:   "aptos",
+	},
+	MultiAptosSignType: {
+		SigLength: 64*32 + 4,
+		PubLength: 32*32 + 1,
+		SigName:   "multiAptos",
+	},
+	TypedEthereumSignType: {
+		SigLength: 65,
+		PubLength: 42,
+		SigName:   "typedEthereum",
+	},
 }
 
 type Bundle struct {
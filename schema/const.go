@@ -13,6 +13,25 @@ const (
 
 	// concurrent submit chunks min size
 	DEFAULT_CHUNK_CONCURRENT_NUM = 50 // default concurrent number
+	// concurrent get block headers
+	DEFAULT_BLOCK_CONCURRENT_NUM = 10
+	// blocks fetched and verified at a time when verifying a block range
+	BLOCK_VERIFY_BATCH_SIZE = 100
+
+	// number of recent blocks tracked to resolve forks when subscribing blocks
+	BLOCK_TRACK_DEPTH = 50
+
+	// a tx anchored to a block can only be mined within this many blocks after the anchor
+	MAX_TX_ANCHOR_DEPTH = 50
+	// concurrent get tx status when watching txs
+	DEFAULT_TX_WATCH_CONCURRENT_NUM = 20
+
+	// max size of the names and values of the tags of a tx
+	MAX_TX_TAGS_SIZE = 2048
+	// max size of the data of a format 1 tx
+	MAX_TX_V1_DATA_SIZE = 10 * 1024 * 1024
+	// owner of a RSA-4096 wallet
+	TX_OWNER_SIZE = 512
 
 	// number of bits in a big.Word
 	WordBits = 32 << (uint64(^big.Word(0)) >> 63)
@@ -35,6 +54,15 @@ const (
 	ERROR_DELAY = 1000 * 40
 )
 
+const (
+	BlockEventNew   = "block"
+	BlockEventReorg = "reorg"
+
+	MempoolEventEntered = "entered"
+	MempoolEventMined   = "mined"
+	MempoolEventDropped = "dropped"
+)
+
 // Errors from /chunk we should never try and continue on.
 var FATAL_CHUNK_UPLOAD_ERRORS = map[string]struct{}{
 	"{\"error\":\"disk_full\"}":                        struct{}{},
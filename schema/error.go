@@ -1,6 +1,10 @@
 package schema
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
 
 var (
 	ErrNotFound     = errors.New("Not Found")
@@ -8,4 +12,22 @@ var (
 	ErrInvalidId    = errors.New("Invalid ArId")
 	ErrBadGateway   = errors.New("Bad Gateway")
 	ErrRequestLimit = errors.New("Arweave gateway request limit")
+	ErrTxDropped    = errors.New("Transaction dropped, anchor expired")
+
+	ErrFeeExceedsMax = errors.New("Transaction price exceeds max fee")
+
+	ErrUnsupportedBlockVersion = errors.New("Unsupported block version")
+	ErrUnverifiable            = errors.New("Unverifiable, the proof is not served")
 )
+
+// ErrInsufficientFunds returned by the wallet pre-flight check, before the tx is signed
+type ErrInsufficientFunds struct {
+	Balance   *big.Int // confirmed balance of the wallet
+	Pending   *big.Int // spent by txs of this process not mined yet
+	Required  *big.Int // reward + quantity of the tx
+	Shortfall *big.Int
+}
+
+func (e *ErrInsufficientFunds) Error() string {
+	return fmt.Sprintf("Insufficient funds, balance: %s, pending: %s, required: %s, shortfall: %s winston", e.Balance, e.Pending, e.Required, e.Shortfall)
+}
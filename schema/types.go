@@ -37,3 +37,41 @@ type BundlrResp struct {
 	Block               int64    `json:"block"`
 	ValidatorSignatures []string `json:"validatorSignatures"`
 }
+
+// BlockDivergence the first block of a range that does not verify
+type BlockDivergence struct {
+	Height    int64  `json:"height"`
+	IndepHash string `json:"indep_hash"`
+	Reason    string `json:"reason"`
+}
+
+// BlockEvent emitted by Client.SubscribeBlocks
+type BlockEvent struct {
+	Type  string `json:"type"` // BlockEventNew or BlockEventReorg
+	Block *Block `json:"block,omitempty"`
+	// only for reorg events, RolledBack is ordered from the old tip down,
+	// Replaced is the new branch ordered from the fork point up to the new tip
+	RolledBack []*Block `json:"rolledBack,omitempty"`
+	Replaced   []*Block `json:"replaced,omitempty"`
+}
+
+// TxWatchResult final state of a tx watched by TxWatcher
+type TxWatchResult struct {
+	TxId   string    `json:"txId"`
+	Status *TxStatus `json:"status,omitempty"`
+	Err    error     `json:"-"` // ErrTxDropped when the tx can no longer be mined
+}
+
+// ManagedTxResult outcome of Wallet.SendTransactionManaged
+type ManagedTxResult struct {
+	TxIds  []string  `json:"txIds"` // every id the tx was signed with, in order, the last one is the current one
+	Status *TxStatus `json:"status,omitempty"`
+}
+
+// MempoolEvent emitted by MempoolWatcher
+type MempoolEvent struct {
+	Type   string       `json:"type"` // MempoolEventEntered, MempoolEventMined or MempoolEventDropped
+	TxId   string       `json:"txId"`
+	Tx     *Transaction `json:"tx,omitempty"`
+	Status *TxStatus    `json:"status,omitempty"` // only for mined events
+}
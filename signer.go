@@ -11,6 +11,24 @@ import (
 	"github.com/permadao/goar/utils"
 )
 
+// TxSigner signs txs and messages for an arweave wallet, it is implemented by Signer
+// which holds the RSA key in memory and by RemoteSigner which delegates to a signing process.
+// The interface has no Address method: Signer keeps its exported Address field, which a method of
+// the same name would conflict with. The address of a TxSigner is the hash of its owner, see TxSignerAddress.
+type TxSigner interface {
+	Owner() string
+	SignTx(tx *schema.Transaction) error
+	SignMsg(msg []byte) ([]byte, error)
+}
+
+// TxSignerAddress the address of the wallet of s, the Address field of a *Signer is returned as is
+func TxSignerAddress(s TxSigner) (string, error) {
+	if signer, ok := s.(*Signer); ok {
+		return signer.Address, nil
+	}
+	return utils.OwnerToAddress(s.Owner())
+}
+
 type Signer struct {
 	Address string
 	PubKey  *rsa.PublicKey
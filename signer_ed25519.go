@@ -0,0 +1,107 @@
+package goar
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+)
+
+// Ed25519Signer signs bundle items with an ed25519 key, as ANS-104 type 2 (ed25519), type 4 (solana)
+// or type 5 (aptos). The owner of its items is the public key and the address its base58 encoding,
+// or the aptos account address for aptos. Type 4 signs the hex encoded message, as arbundles
+// HexInjectedSolanaSigner does with the signMessage of solana wallets.
+type Ed25519Signer struct {
+	SignType int
+	PubKey   ed25519.PublicKey
+	PrvKey   ed25519.PrivateKey
+}
+
+// NewEd25519Signer signer of type 2 from a 32 bytes seed
+func NewEd25519Signer(seed []byte) (*Ed25519Signer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("ed25519 seed must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	prvKey := ed25519.NewKeyFromSeed(seed)
+	return &Ed25519Signer{
+		SignType: schema.ED25519SignType,
+		PubKey:   prvKey.Public().(ed25519.PublicKey),
+		PrvKey:   prvKey,
+	}, nil
+}
+
+// NewSolanaSigner signer of type 2 from a base58 secret key, as exported by Phantom and Solflare,
+// as arbundles SolanaSigner
+func NewSolanaSigner(secretKey string) (*Ed25519Signer, error) {
+	return newSolanaSigner(base58.Decode(secretKey))
+}
+
+// NewSolanaSignerFromJSON signer of type 2 from a keypair JSON array, as written by solana-keygen
+func NewSolanaSignerFromJSON(b []byte) (*Ed25519Signer, error) {
+	keypair := make([]byte, 0, ed25519.PrivateKeySize)
+	if err := json.Unmarshal(b, &keypair); err != nil {
+		return nil, err
+	}
+	return newSolanaSigner(keypair)
+}
+
+func NewSolanaSignerFromPath(path string) (*Ed25519Signer, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewSolanaSignerFromJSON(b)
+}
+
+// the solana secret key is the seed followed by the public key
+func newSolanaSigner(keypair []byte) (*Ed25519Signer, error) {
+	if len(keypair) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("solana keypair must be %d bytes, got %d", ed25519.PrivateKeySize, len(keypair))
+	}
+	signer, err := NewEd25519Signer(keypair[:ed25519.SeedSize])
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(signer.PubKey, keypair[ed25519.SeedSize:]) {
+		return nil, errors.New("solana keypair public key does not match its secret key")
+	}
+	return signer, nil
+}
+
+// NewAptosSigner signer of type 5 from the 32 bytes private key of an aptos account
+func NewAptosSigner(prvKey []byte) (*Ed25519Signer, error) {
+	signer, err := NewEd25519Signer(prvKey)
+	if err != nil {
+		return nil, err
+	}
+	signer.SignType = schema.AptosSignType
+	return signer, nil
+}
+
+func (s *Ed25519Signer) Owner() string {
+	return utils.Base64Encode(s.PubKey)
+}
+
+func (s *Ed25519Signer) Address() string {
+	if s.SignType == schema.AptosSignType {
+		return utils.AptosAddress(s.PubKey)
+	}
+	return base58.Encode(s.PubKey)
+}
+
+// SignMsg signs msg, aptos and solana signers sign the message their wallets sign for msg
+func (s *Ed25519Signer) SignMsg(msg []byte) ([]byte, error) {
+	switch s.SignType {
+	case schema.AptosSignType:
+		msg = utils.AptosMessage(msg)
+	case schema.SolanaSignType:
+		msg = utils.SolanaMessage(msg)
+	}
+	return ed25519.Sign(s.PrvKey, msg), nil
+}
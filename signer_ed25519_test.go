@@ -0,0 +1,144 @@
+package goar
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEd25519Signer(t *testing.T) {
+	// RFC 8032 test 1
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	pub, _ := hex.DecodeString("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	signer, err := NewEd25519Signer(seed)
+	assert.NoError(t, err)
+	assert.Equal(t, schema.ED25519SignType, signer.SignType)
+	assert.Equal(t, utils.Base64Encode(pub), signer.Owner())
+	assert.Equal(t, base58.Encode(pub), signer.Address())
+
+	_, err = NewEd25519Signer(seed[:31])
+	assert.Error(t, err)
+
+	// solana keypair formats
+	keypair := append(append([]byte{}, seed...), pub...)
+	solSigner, err := NewSolanaSigner(base58.Encode(keypair))
+	assert.NoError(t, err)
+	// as arbundles SolanaSigner
+	assert.Equal(t, schema.ED25519SignType, solSigner.SignType)
+	assert.Equal(t, signer.Address(), solSigner.Address())
+
+	ints := make([]int, len(keypair))
+	for i, b := range keypair {
+		ints[i] = int(b)
+	}
+	js, _ := json.Marshal(ints)
+	solSigner, err = NewSolanaSignerFromJSON(js)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address(), solSigner.Address())
+
+	keypair[63] ^= 1
+	_, err = NewSolanaSigner(base58.Encode(keypair))
+	assert.EqualError(t, err, "solana keypair public key does not match its secret key")
+}
+
+func TestBundle_Ed25519(t *testing.T) {
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	edSigner, err := NewEd25519Signer(seed)
+	assert.NoError(t, err)
+	// type 4 signs as a solana wallet through signMessage
+	solSigner, err := NewSolanaSigner(base58.Encode(edSigner.PrvKey))
+	assert.NoError(t, err)
+	solSigner.SignType = schema.SolanaSignType
+
+	items := make([]schema.BundleItem, 0)
+	for _, signer := range []*Ed25519Signer{edSigner, solSigner} {
+		b, err := NewBundler(signer)
+		assert.NoError(t, err)
+		assert.Equal(t, signer.SignType, b.SignType)
+		item, err := b.CreateAndSignItem([]byte("ed25519 foo"), "", "", []schema.Tag{{Name: "Content-Type", Value: "application/txt"}})
+		assert.NoError(t, err)
+		items = append(items, item)
+	}
+
+	bundle, err := utils.NewBundle(items...)
+	assert.NoError(t, err)
+	resBundle, err := utils.DecodeBundle(bundle.Binary)
+	assert.NoError(t, err)
+	assert.Len(t, resBundle.Items, 2)
+	for i, item := range resBundle.Items {
+		assert.Equal(t, items[i].SignatureType, item.SignatureType)
+		assert.NoError(t, utils.VerifyBundleItem(item))
+		addr, err := utils.ItemSignerAddr(item)
+		assert.NoError(t, err)
+		assert.Equal(t, edSigner.Address(), addr)
+	}
+
+	// the type 4 signature is over the hex message
+	signMsg, err := utils.BundleItemSignData(items[1])
+	assert.NoError(t, err)
+	sig, err := utils.Base64Decode(items[1].Signature)
+	assert.NoError(t, err)
+	assert.True(t, ed25519.Verify(edSigner.PubKey, []byte(hex.EncodeToString(signMsg)), sig))
+}
+
+func TestBundle_Aptos(t *testing.T) {
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	signer, err := NewAptosSigner(seed)
+	assert.NoError(t, err)
+	b, err := NewBundler(signer)
+	assert.NoError(t, err)
+	assert.Equal(t, schema.AptosSignType, b.SignType)
+	item, err := b.CreateAndSignItem([]byte("aptos foo"), "", "", nil)
+	assert.NoError(t, err)
+
+	// multi aptos item signed by 2 of 3 keys
+	keys := make([]*Ed25519Signer, 3)
+	pubKeys := make([][]byte, 3)
+	for i := range keys {
+		keys[i], err = NewEd25519Signer(append(seed[:31:31], byte(i)))
+		assert.NoError(t, err)
+		pubKeys[i] = keys[i].PubKey
+	}
+	owner, err := utils.NewAptosMultiOwner(pubKeys, 2)
+	assert.NoError(t, err)
+	multiItem, err := utils.NewBundleItem(utils.Base64Encode(owner), schema.MultiAptosSignType, "", "", []byte("multi aptos foo"), nil)
+	assert.NoError(t, err)
+	signMsg, err := utils.BundleItemSignData(multiItem)
+	assert.NoError(t, err)
+	sigs := map[int][]byte{}
+	for _, i := range []int{0, 2} {
+		sigs[i], err = keys[i].SignMsg(signMsg)
+		assert.NoError(t, err)
+	}
+	sig, err := utils.NewAptosMultiSignature(sigs)
+	assert.NoError(t, err)
+	id := sha256.Sum256(sig)
+	multiItem.Signature = utils.Base64Encode(sig)
+	multiItem.Id = utils.Base64Encode(id[:])
+	multiItem.Binary, err = utils.GenerateItemBinary(multiItem)
+	assert.NoError(t, err)
+
+	bundle, err := utils.NewBundle(item, multiItem)
+	assert.NoError(t, err)
+	resBundle, err := utils.DecodeBundle(bundle.Binary)
+	assert.NoError(t, err)
+	assert.Len(t, resBundle.Items, 2)
+	for _, it := range resBundle.Items {
+		assert.NoError(t, utils.VerifyBundleItem(it))
+	}
+	addr, err := utils.ItemSignerAddr(resBundle.Items[0])
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address(), addr)
+	multiAddr, err := utils.ItemSignerAddr(resBundle.Items[1])
+	assert.NoError(t, err)
+	expected, err := utils.AptosMultiAddress(owner)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, multiAddr)
+}
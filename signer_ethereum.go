@@ -0,0 +1,34 @@
+package goar
+
+import (
+	"strings"
+
+	"github.com/everFinance/goether"
+	"github.com/permadao/goar/utils"
+)
+
+// TypedEthereumSigner signs bundle items as ANS-104 type 7, with EIP-712 typed data as MetaMask
+// eth_signTypedData_v4 does. The owner of its items is the lowercase 0x address instead of the public key.
+type TypedEthereumSigner struct {
+	Signer *goether.Signer
+}
+
+func NewTypedEthereumSigner(signer *goether.Signer) *TypedEthereumSigner {
+	return &TypedEthereumSigner{Signer: signer}
+}
+
+func (s *TypedEthereumSigner) Owner() string {
+	return utils.Base64Encode([]byte(s.owner()))
+}
+
+func (s *TypedEthereumSigner) Address() string {
+	return s.Signer.Address.String()
+}
+
+func (s *TypedEthereumSigner) SignMsg(msg []byte) ([]byte, error) {
+	return s.Signer.SignTypedData(utils.TypedEthereumData(s.owner(), msg))
+}
+
+func (s *TypedEthereumSigner) owner() string {
+	return strings.ToLower(s.Signer.Address.String())
+}
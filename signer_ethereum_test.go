@@ -0,0 +1,67 @@
+package goar
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundle_TypedEthereum(t *testing.T) {
+	signer := NewTypedEthereumSigner(signer01)
+	b, err := NewBundler(signer)
+	assert.NoError(t, err)
+	assert.Equal(t, schema.TypedEthereumSignType, b.SignType)
+	assert.Equal(t, signer01.Address.String(), b.Address)
+
+	item, err := b.CreateAndSignItem([]byte("typed eth foo"), "", "", []schema.Tag{{Name: "Content-Type", Value: "application/txt"}})
+	assert.NoError(t, err)
+	owner, err := utils.Base64Decode(item.Owner)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.ToLower(signer01.Address.String()), string(owner))
+
+	bundle, err := utils.NewBundle(item)
+	assert.NoError(t, err)
+	resBundle, err := utils.DecodeBundle(bundle.Binary)
+	assert.NoError(t, err)
+	decoded := resBundle.Items[0]
+	assert.Equal(t, schema.TypedEthereumSignType, decoded.SignatureType)
+	assert.Equal(t, item.Owner, decoded.Owner)
+	assert.NoError(t, utils.VerifyBundleItem(decoded))
+	addr, err := utils.ItemSignerAddr(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, signer01.Address.String(), addr)
+
+	// the signed digest is the eip712 encoding of arbundles TypedEthereumSigner, computed by hand
+	signMsg, err := utils.BundleItemSignData(decoded)
+	assert.NoError(t, err)
+	domainType := crypto.Keccak256([]byte("EIP712Domain(string name,string version)"))
+	domain := crypto.Keccak256(domainType, crypto.Keccak256([]byte("Bundlr")), crypto.Keccak256([]byte("1")))
+	msgType := crypto.Keccak256([]byte("Bundlr(bytes Transaction hash,address address)"))
+	msgHash := crypto.Keccak256(msgType, crypto.Keccak256(signMsg), common.LeftPadBytes(signer01.Address.Bytes(), 32))
+	digest := crypto.Keccak256([]byte{0x19, 0x01}, domain, msgHash)
+	hash, err := utils.TypedEthereumHash(string(owner), signMsg)
+	assert.NoError(t, err)
+	assert.Equal(t, digest, hash)
+	typedSig, err := utils.Base64Decode(decoded.Signature)
+	assert.NoError(t, err)
+	assert.Len(t, typedSig, 65)
+	rsv := append([]byte{}, typedSig...)
+	rsv[64] -= 27
+	pub, err := crypto.SigToPub(digest, rsv)
+	assert.NoError(t, err)
+	assert.Equal(t, signer01.Address, crypto.PubkeyToAddress(*pub))
+
+	// a personal_sign signature is not a typed signature
+	sig, err := signer01.SignMsg(signMsg)
+	assert.NoError(t, err)
+	id := sha256.Sum256(sig)
+	decoded.Signature = utils.Base64Encode(sig)
+	decoded.Id = utils.Base64Encode(id[:])
+	assert.EqualError(t, utils.VerifyBundleItem(decoded), "verify typed ethereum sign failed")
+}
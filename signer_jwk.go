@@ -0,0 +1,162 @@
+package goar
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/everFinance/gojwk"
+	"github.com/permadao/goar/utils"
+)
+
+const (
+	// arweave wallets are RSA-4096 keys with the public exponent 65537
+	walletKeyBits     = 4096
+	walletKeyExponent = 65537
+)
+
+// jwk RSA key as written by arweave-js, arweave.app and ArConnect
+type jwk struct {
+	Kty string `json:"kty"`
+	E   string `json:"e"`
+	N   string `json:"n"`
+	D   string `json:"d,omitempty"`
+	P   string `json:"p,omitempty"`
+	Q   string `json:"q,omitempty"`
+	Dp  string `json:"dp,omitempty"`
+	Dq  string `json:"dq,omitempty"`
+	Qi  string `json:"qi,omitempty"`
+}
+
+// GenerateWallet creates the key of a new arweave wallet, save it with Signer.MarshalJWK
+func GenerateWallet() (*Signer, error) {
+	prvKey, err := utils.GenerateRsaKey(walletKeyBits)
+	if err != nil {
+		return nil, err
+	}
+	if err = CheckWalletKey(&prvKey.PublicKey); err != nil {
+		return nil, err
+	}
+	return NewSignerByPrivateKey(prvKey), nil
+}
+
+// CheckWalletKey checks pubKey is a 4096 bits key with the exponent 65537, as required by arweave
+func CheckWalletKey(pubKey *rsa.PublicKey) error {
+	if pubKey.N.BitLen() != walletKeyBits {
+		return fmt.Errorf("wallet key must be %d bits, got %d", walletKeyBits, pubKey.N.BitLen())
+	}
+	if pubKey.E != walletKeyExponent {
+		return fmt.Errorf("wallet key exponent must be %d, got %d", walletKeyExponent, pubKey.E)
+	}
+	return nil
+}
+
+// MarshalJWK exports the private key as an arweave keyfile, including the CRT parameters
+func (s *Signer) MarshalJWK() ([]byte, error) {
+	prvKey, err := s.fullPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := publicJWK(&prvKey.PublicKey)
+	key.D = utils.Base64Encode(prvKey.D.Bytes())
+	key.P = utils.Base64Encode(prvKey.Primes[0].Bytes())
+	key.Q = utils.Base64Encode(prvKey.Primes[1].Bytes())
+	key.Dp = utils.Base64Encode(prvKey.Precomputed.Dp.Bytes())
+	key.Dq = utils.Base64Encode(prvKey.Precomputed.Dq.Bytes())
+	key.Qi = utils.Base64Encode(prvKey.Precomputed.Qinv.Bytes())
+	return json.Marshal(key)
+}
+
+// MarshalPublicJWK exports the public key only, eg: for signature verification services
+func (s *Signer) MarshalPublicJWK() ([]byte, error) {
+	return json.Marshal(publicJWK(s.PubKey))
+}
+
+// ParsePublicJWK loads a public JWK, the private part is ignored when present
+func ParsePublicJWK(b []byte) (*rsa.PublicKey, error) {
+	key, err := gojwk.Unmarshal(b)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := key.DecodePublicKey()
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := pubKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("pubKey type error")
+	}
+	return pub, nil
+}
+
+// fullPrivateKey returns the private key with its primes and CRT values, which are required by the export formats
+func (s *Signer) fullPrivateKey() (*rsa.PrivateKey, error) {
+	prvKey := s.PrvKey
+	if len(prvKey.Primes) != 2 {
+		// keys loaded by NewSigner only have n, e and d
+		p, q, err := recoverPrimes(prvKey.N, big.NewInt(int64(prvKey.E)), prvKey.D)
+		if err != nil {
+			return nil, err
+		}
+		prvKey = &rsa.PrivateKey{PublicKey: prvKey.PublicKey, D: prvKey.D, Primes: []*big.Int{p, q}}
+	}
+	if err := prvKey.Validate(); err != nil {
+		return nil, err
+	}
+	prvKey.Precompute()
+	return prvKey, nil
+}
+
+func publicJWK(pubKey *rsa.PublicKey) *jwk {
+	return &jwk{
+		Kty: "RSA",
+		E:   utils.Base64Encode(big.NewInt(int64(pubKey.E)).Bytes()),
+		N:   utils.Base64Encode(pubKey.N.Bytes()),
+	}
+}
+
+// recoverPrimes factors n from the exponents e and d
+func recoverPrimes(n, e, d *big.Int) (*big.Int, *big.Int, error) {
+	one := big.NewInt(1)
+	nMinus1 := new(big.Int).Sub(n, one)
+	// e*d - 1 = 2^t * r with r odd
+	k := new(big.Int).Mul(e, d)
+	k.Sub(k, one)
+	r := new(big.Int).Set(k)
+	t := 0
+	for r.Bit(0) == 0 {
+		r.Rsh(r, 1)
+		t++
+	}
+	if t == 0 {
+		return nil, nil, errors.New("invalid rsa private exponent")
+	}
+
+	for g := int64(2); g < 1000; g++ {
+		y := new(big.Int).Exp(big.NewInt(g), r, n)
+		if y.Cmp(one) == 0 || y.Cmp(nMinus1) == 0 {
+			continue
+		}
+		// g^(2^t * r) = 1, the last y different from 1 is a square root of 1
+		for i := 1; i <= t; i++ {
+			x := new(big.Int).Exp(y, big.NewInt(2), n)
+			if x.Cmp(one) == 0 {
+				// y is a non trivial square root of 1
+				p := new(big.Int).GCD(nil, nil, new(big.Int).Sub(y, one), n)
+				q := new(big.Int).Div(n, p)
+				if p.Cmp(q) < 0 {
+					p, q = q, p
+				}
+				return p, q, nil
+			}
+			if x.Cmp(nMinus1) == 0 {
+				break
+			}
+			y = x
+		}
+	}
+	return nil, nil, errors.New("can not recover rsa primes")
+}
@@ -0,0 +1,94 @@
+package goar
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateWallet(t *testing.T) {
+	signer, err := GenerateWallet()
+	assert.NoError(t, err)
+	assert.NoError(t, CheckWalletKey(signer.PubKey))
+
+	jwk, err := signer.MarshalJWK()
+	assert.NoError(t, err)
+	loaded, err := NewSigner(jwk)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address, loaded.Address)
+
+	msg := []byte("new wallet")
+	sig, err := loaded.SignMsg(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, utils.Verify(msg, signer.PubKey, sig))
+}
+
+func TestSigner_MarshalJWK(t *testing.T) {
+	// loaded keys have no primes, they are recovered
+	signer, err := NewSignerFromPath("testKey.json")
+	assert.NoError(t, err)
+	assert.NoError(t, CheckWalletKey(signer.PubKey))
+	by, err := signer.MarshalJWK()
+	assert.NoError(t, err)
+
+	key := map[string]string{}
+	assert.NoError(t, json.Unmarshal(by, &key))
+	for _, field := range []string{"kty", "e", "n", "d", "p", "q", "dp", "dq", "qi"} {
+		assert.NotEmpty(t, key[field], field)
+	}
+	assert.Equal(t, "AQAB", key["e"])
+	p, _ := utils.Base64Decode(key["p"])
+	q, _ := utils.Base64Decode(key["q"])
+	n := new(big.Int).Mul(new(big.Int).SetBytes(p), new(big.Int).SetBytes(q))
+	assert.Equal(t, signer.PubKey.N, n)
+
+	pub, err := signer.MarshalPublicJWK()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"kty":"RSA","e":"AQAB","n":"`+signer.Owner()+`"}`, string(pub))
+	pubKey, err := ParsePublicJWK(pub)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.PubKey.N, pubKey.N)
+	assert.Equal(t, 65537, pubKey.E)
+}
+
+func TestRecoverPrimes_LambdaExponent(t *testing.T) {
+	// openssl style keys use d = e^-1 mod lcm(p-1, q-1), with p = q = 3 mod 4 and an odd
+	// (e*d-1)/lcm the only non trivial square root of 1 is found by the last squaring
+	one := big.NewInt(1)
+	e := big.NewInt(65537)
+	var p, q, d *big.Int
+	for {
+		var err error
+		p, err = rand.Prime(rand.Reader, 256)
+		assert.NoError(t, err)
+		q, err = rand.Prime(rand.Reader, 256)
+		assert.NoError(t, err)
+		if p.Bit(1) == 0 || q.Bit(1) == 0 || p.Cmp(q) == 0 {
+			continue
+		}
+		pm1, qm1 := new(big.Int).Sub(p, one), new(big.Int).Sub(q, one)
+		lambda := new(big.Int).Mul(pm1, qm1)
+		lambda.Div(lambda, new(big.Int).GCD(nil, nil, pm1, qm1))
+		if d = new(big.Int).ModInverse(e, lambda); d == nil {
+			continue
+		}
+		k := new(big.Int).Mul(e, d)
+		k.Sub(k, one).Div(k, lambda)
+		if k.Bit(0) == 1 {
+			break
+		}
+	}
+	n := new(big.Int).Mul(p, q)
+	rp, rq, err := recoverPrimes(n, e, d)
+	assert.NoError(t, err)
+	assert.Equal(t, n, new(big.Int).Mul(rp, rq))
+	if p.Cmp(q) < 0 {
+		p, q = q, p
+	}
+	assert.Equal(t, p, rp)
+	assert.Equal(t, q, rq)
+}
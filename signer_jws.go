@@ -0,0 +1,162 @@
+package goar
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/permadao/goar/utils"
+)
+
+const jwsAlgPS256 = "PS256"
+
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid"`
+	JWK *jwk   `json:"jwk,omitempty"`
+}
+
+// JWTClaims registered claims checked by VerifyJWT, times are unix seconds
+type JWTClaims struct {
+	Issuer    string `json:"iss,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	Audience  string `json:"aud,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	NotBefore int64  `json:"nbf,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ID        string `json:"jti,omitempty"`
+}
+
+// SignJWS signs payload as a PS256 compact JWS, kid is the address of the wallet.
+// embedJWK adds the public key to the header, for verifiers which only know the address.
+func (s *Signer) SignJWS(payload []byte, embedJWK bool) (string, error) {
+	return s.signJWS("", payload, embedJWK)
+}
+
+// SignJWT signs claims as a PS256 JWT, claims is any JSON object, eg: JWTClaims or a struct embedding it
+func (s *Signer) SignJWT(claims interface{}, embedJWK bool) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.HasPrefix(payload, []byte("{")) {
+		return "", errors.New("jwt claims must be a JSON object")
+	}
+	return s.signJWS("JWT", payload, embedJWK)
+}
+
+func (s *Signer) signJWS(typ string, payload []byte, embedJWK bool) (string, error) {
+	header := jwsHeader{Alg: jwsAlgPS256, Typ: typ, Kid: s.Address}
+	if embedJWK {
+		header.JWK = publicJWK(s.PubKey)
+	}
+	by, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	signingInput := utils.Base64Encode(by) + "." + utils.Base64Encode(payload)
+	hashed := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPSS(rand.Reader, s.PrvKey, crypto.SHA256, hashed[:], &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       crypto.SHA256,
+	})
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + utils.Base64Encode(sig), nil
+}
+
+// VerifyJWS verifies a PS256 JWS of the wallet owner and returns its payload and the address of the signer.
+// When owner is empty the key embedded in the header is used, the caller must then check the address is trusted.
+func VerifyJWS(token, owner string) (payload []byte, address string, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, "", errors.New("invalid jws: 3 parts expected")
+	}
+	by, err := utils.Base64Decode(parts[0])
+	if err != nil {
+		return nil, "", err
+	}
+	header := jwsHeader{}
+	if err = json.Unmarshal(by, &header); err != nil {
+		return nil, "", err
+	}
+	if header.Alg != jwsAlgPS256 {
+		return nil, "", fmt.Errorf("unsupported jws alg: %s", header.Alg)
+	}
+	if owner == "" {
+		if header.JWK == nil {
+			return nil, "", errors.New("jws has no jwk, owner is required")
+		}
+		if header.JWK.Kty != "RSA" || header.JWK.E != "AQAB" {
+			return nil, "", errors.New("jws jwk is not an arweave key")
+		}
+		owner = header.JWK.N
+	}
+	pubKey, err := utils.OwnerToPubKey(owner)
+	if err != nil {
+		return nil, "", err
+	}
+	if err = CheckWalletKey(pubKey); err != nil {
+		return nil, "", err
+	}
+	if address, err = utils.OwnerToAddress(owner); err != nil {
+		return nil, "", err
+	}
+	if header.Kid != address {
+		return nil, "", errors.New("jws kid is not the address of the key")
+	}
+
+	sig, err := utils.Base64Decode(parts[2])
+	if err != nil {
+		return nil, "", err
+	}
+	hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPSS(pubKey, crypto.SHA256, hashed[:], sig, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthAuto,
+		Hash:       crypto.SHA256,
+	}); err != nil {
+		return nil, "", err
+	}
+	if payload, err = utils.Base64Decode(parts[1]); err != nil {
+		return nil, "", err
+	}
+	return payload, address, nil
+}
+
+// VerifyJWT verifies a JWT as VerifyJWS, checks exp and nbf and unmarshals the payload into claims
+func VerifyJWT(token, owner string, claims interface{}) (address string, err error) {
+	payload, address, err := VerifyJWS(token, owner)
+	if err != nil {
+		return "", err
+	}
+	// only exp and nbf, aud may be an array
+	registered := struct {
+		ExpiresAt float64 `json:"exp"`
+		NotBefore float64 `json:"nbf"`
+	}{}
+	if err = json.Unmarshal(payload, &registered); err != nil {
+		return "", err
+	}
+	now := float64(time.Now().Unix())
+	if registered.ExpiresAt != 0 && now >= registered.ExpiresAt {
+		return "", errors.New("jwt expired")
+	}
+	if registered.NotBefore != 0 && now < registered.NotBefore {
+		return "", errors.New("jwt not valid yet")
+	}
+	if claims != nil {
+		if err = json.Unmarshal(payload, claims); err != nil {
+			return "", err
+		}
+	}
+	return address, nil
+}
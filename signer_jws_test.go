@@ -0,0 +1,78 @@
+package goar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner_SignJWS(t *testing.T) {
+	signer, err := NewSignerFromPath("testKey.json")
+	assert.NoError(t, err)
+
+	token, err := signer.SignJWS([]byte("payload"), false)
+	assert.NoError(t, err)
+	header, _ := utils.Base64Decode(strings.Split(token, ".")[0])
+	assert.Equal(t, `{"alg":"PS256","kid":"`+signer.Address+`"}`, string(header))
+
+	payload, addr, err := VerifyJWS(token, signer.Owner())
+	assert.NoError(t, err)
+	assert.Equal(t, "payload", string(payload))
+	assert.Equal(t, signer.Address, addr)
+	_, _, err = VerifyJWS(token, "")
+	assert.EqualError(t, err, "jws has no jwk, owner is required")
+
+	other, err := GenerateWallet()
+	assert.NoError(t, err)
+	_, _, err = VerifyJWS(token, other.Owner())
+	assert.EqualError(t, err, "jws kid is not the address of the key")
+
+	// embedded key
+	token, err = signer.SignJWS([]byte("payload"), true)
+	assert.NoError(t, err)
+	_, addr, err = VerifyJWS(token, "")
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address, addr)
+
+	parts := strings.Split(token, ".")
+	parts[1] = utils.Base64Encode([]byte("tampered"))
+	_, _, err = VerifyJWS(strings.Join(parts, "."), "")
+	assert.Error(t, err)
+}
+
+func TestSigner_SignJWT(t *testing.T) {
+	signer, err := NewSignerFromPath("testKey.json")
+	assert.NoError(t, err)
+	type claims struct {
+		JWTClaims
+		Role string `json:"role"`
+	}
+
+	token, err := signer.SignJWT(claims{
+		JWTClaims: JWTClaims{Subject: "user", ExpiresAt: time.Now().Add(time.Minute).Unix()},
+		Role:      "admin",
+	}, true)
+	assert.NoError(t, err)
+	got := claims{}
+	addr, err := VerifyJWT(token, "", &got)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address, addr)
+	assert.Equal(t, "user", got.Subject)
+	assert.Equal(t, "admin", got.Role)
+
+	token, err = signer.SignJWT(JWTClaims{ExpiresAt: time.Now().Add(-time.Minute).Unix()}, true)
+	assert.NoError(t, err)
+	_, err = VerifyJWT(token, "", nil)
+	assert.EqualError(t, err, "jwt expired")
+
+	token, err = signer.SignJWT(JWTClaims{NotBefore: time.Now().Add(time.Hour).Unix()}, false)
+	assert.NoError(t, err)
+	_, err = VerifyJWT(token, signer.Owner(), nil)
+	assert.EqualError(t, err, "jwt not valid yet")
+
+	_, err = signer.SignJWT([]string{"not an object"}, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package goar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+)
+
+// SignMessage signs data for off-chain verification, compatible with ArConnect signMessage.
+// Unlike SignMsg the data is hashed first, the signature can not be used as a tx signature.
+func (s *Signer) SignMessage(data []byte) ([]byte, error) {
+	return utils.SignMessage(data, s.PrvKey)
+}
+
+// VerifyMessage verifies a SignMessage or ArConnect signMessage signature of the wallet with owner
+func VerifyMessage(data, signature []byte, owner string) error {
+	pubKey, err := utils.OwnerToPubKey(owner)
+	if err != nil {
+		return err
+	}
+	return utils.VerifyMessage(data, pubKey, signature)
+}
+
+// VerifyMessageByAddress verifies a message signature of address, its owner is looked up on the network
+func (c *Client) VerifyMessageByAddress(data, signature []byte, address string) error {
+	owner, err := c.GetOwnerByAddress(address)
+	if err != nil {
+		return err
+	}
+	return VerifyMessage(data, signature, owner)
+}
+
+// GetOwnerByAddress returns the public key of a wallet from its last tx, or from graphql when the
+// node does not have it. It is only known once the wallet has sent a tx.
+func (c *Client) GetOwnerByAddress(address string) (string, error) {
+	if by, err := utils.Base64Decode(address); err != nil || len(by) != 32 {
+		return "", fmt.Errorf("invalid address: %s", address)
+	}
+	owner := ""
+	if id, err := c.GetLastTransactionID(address); err == nil && id != "" {
+		owner, _ = c.GetTransactionField(id, "owner")
+	}
+	if owner == "" {
+		var err error
+		if owner, err = c.getOwnerByGraphQL(address); err != nil {
+			return "", err
+		}
+	}
+	if addr, err := utils.OwnerToAddress(owner); err != nil || addr != address {
+		return "", errors.New("owner does not match the address")
+	}
+	return owner, nil
+}
+
+func (c *Client) getOwnerByGraphQL(address string) (string, error) {
+	data, err := c.GraphQL(fmt.Sprintf(`{transactions(owners: ["%s"], first: 1) {edges {node {owner {key}}}}}`, address))
+	if err != nil {
+		return "", err
+	}
+	res := struct {
+		Transactions struct {
+			Edges []struct {
+				Node struct {
+					Owner struct {
+						Key string `json:"key"`
+					} `json:"owner"`
+				} `json:"node"`
+			} `json:"edges"`
+		} `json:"transactions"`
+	}{}
+	if err = json.Unmarshal(data, &res); err != nil {
+		return "", err
+	}
+	if len(res.Transactions.Edges) == 0 {
+		return "", fmt.Errorf("owner of %s: %w", address, schema.ErrNotFound)
+	}
+	return res.Transactions.Edges[0].Node.Owner.Key, nil
+}
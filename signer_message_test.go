@@ -0,0 +1,76 @@
+package goar
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner_SignMessage(t *testing.T) {
+	signer, err := NewSignerFromPath("testKey.json")
+	assert.NoError(t, err)
+	msg := []byte("sign in to goar")
+
+	sig, err := signer.SignMessage(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyMessage(msg, sig, signer.Owner()))
+	assert.Error(t, VerifyMessage([]byte("sign in to goar!"), sig, signer.Owner()))
+
+	// a raw SignMsg signature is not a message signature
+	rawSig, err := signer.SignMsg(msg)
+	assert.NoError(t, err)
+	assert.Error(t, VerifyMessage(msg, rawSig, signer.Owner()))
+
+	other, err := GenerateWallet()
+	assert.NoError(t, err)
+	assert.Error(t, VerifyMessage(msg, sig, other.Owner()))
+}
+
+func TestVerifyMessage_Fixture(t *testing.T) {
+	// signed outside goar by openssl the way ArConnect signMessage signs with WebCrypto:
+	// RSA-PSS sha256 with a 32 bytes salt over sha256(data), by the wallet of testKey.json
+	signer, err := NewSignerFromPath("testKey.json")
+	assert.NoError(t, err)
+	msg := []byte("sign in to goar with ArConnect")
+	sig, err := utils.Base64Decode("nMWlqn7zuut5LQ64vPY2OKsh8D-B26Ck6w--D9rIHhWrOVoTdIFtQyEy53yu_zw0lqVXbc5DgtN1z-k2ocvYRJY4cvnrd93TjLUVGVKWFNTXhd5Jr-2w-QpiJcUnPVF0-L3bKocNXENkSZIkiHZZzJ_BY4yaF6xoqoMte5_1bxybzWH-wMYp7RruDROOkKoPJH792dF8EC5Oryx20OcKhH6yM38cSVBA5_15jIdyDm4M6u1hysUZMGDU2n8DQamdLb1WpzIP0FFT-XhAYy9NZe2lOmjbTNZWG9t0uxwAadh324Oco7PEEltU7LC0jzgfNTVQYp4x9oSUnmpwZdI6ClmDOl8p8AX3Bob6d9lnK8uwfgPIrsUJGFfXTpunaJpjWZqFvDAgVt61JOOUF3Uj71GcECO08Wr5-sLWMTZTH7Ot-uf7ZDpMjrZfBot3dk5UkOiSXXOjFUMLKMbPPjfNfjxg4DrrRALu5N0IcwULJ9tnIZXs94CGLvHmxzzh1tHOcqydemKyvptTEk9F6z2UdJGylUuuuUQkl8budAJJPeux1Vg9o8MziviJxo6etJGhsRKCaCQCoSP598f3lSYOoqx8buXeGBl6LuKfwD_kOqQgCwTtu76vwc1EgZvXo8exSZ7Srq8qost7ITXzv3NxUsCeDQD6GFZoRoObPavSMUk")
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyMessage(msg, sig, signer.Owner()))
+	assert.Error(t, VerifyMessage([]byte("sign in to goar"), sig, signer.Owner()))
+}
+
+func TestClient_VerifyMessageByAddress(t *testing.T) {
+	signer, err := NewSignerFromPath("testKey.json")
+	assert.NoError(t, err)
+	unknown := utils.Base64Encode(make([]byte, 32))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wallet/" + signer.Address + "/last_tx":
+			w.Write([]byte("last-tx"))
+		case "/wallet/" + unknown + "/last_tx":
+			w.Write([]byte(""))
+		case "/tx/last-tx/owner":
+			w.Write([]byte(signer.Owner()))
+		case "/graphql":
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"transactions": map[string]interface{}{"edges": []interface{}{}}}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	msg := []byte("sign in to goar")
+	sig, err := signer.SignMessage(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, c.VerifyMessageByAddress(msg, sig, signer.Address))
+	assert.Error(t, c.VerifyMessageByAddress([]byte("other"), sig, signer.Address))
+
+	_, err = c.GetOwnerByAddress(unknown)
+	assert.ErrorContains(t, err, "Not Found")
+	_, err = c.GetOwnerByAddress(`x"]) {`)
+	assert.ErrorContains(t, err, "invalid address")
+}
@@ -0,0 +1,41 @@
+package goar
+
+import (
+	"github.com/permadao/goar/utils"
+)
+
+// GenerateMnemonicWallet creates a wallet recoverable from the returned 12 words mnemonic
+// with NewSignerFromMnemonic. Deriving the key takes a few seconds.
+func GenerateMnemonicWallet() (string, *Signer, error) {
+	mnemonic, err := utils.GenerateMnemonic()
+	if err != nil {
+		return "", nil, err
+	}
+	signer, err := NewSignerFromMnemonic(mnemonic)
+	if err != nil {
+		return "", nil, err
+	}
+	return mnemonic, signer, nil
+}
+
+// NewSignerFromMnemonic recovers the wallet of a BIP39 mnemonic, the 4096 bits key is derived from
+// the seed as arweave-mnemonic-keys does, see utils.GenerateRsaKeyFromSeed
+func NewSignerFromMnemonic(mnemonic string) (*Signer, error) {
+	if err := utils.ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	prvKey, err := utils.GenerateRsaKeyFromSeed(utils.MnemonicToSeed(mnemonic, ""), walletKeyBits)
+	if err != nil {
+		return nil, err
+	}
+	return NewSignerByPrivateKey(prvKey), nil
+}
+
+// proxyUrl: option
+func NewWalletFromMnemonic(mnemonic string, clientUrl string, proxyUrl ...string) (*Wallet, error) {
+	signer, err := NewSignerFromMnemonic(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	return NewWalletWithSigner(signer, clientUrl, proxyUrl...), nil
+}
@@ -0,0 +1,37 @@
+package goar
+
+import (
+	"testing"
+
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSignerFromMnemonic(t *testing.T) {
+	mnemonic, signer, err := GenerateMnemonicWallet()
+	assert.NoError(t, err)
+	assert.NoError(t, CheckWalletKey(signer.PubKey))
+
+	// extra spaces do not change the seed
+	recovered, err := NewSignerFromMnemonic("  " + mnemonic + "\n")
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address, recovered.Address)
+	assert.Equal(t, signer.PrvKey.D, recovered.PrvKey.D)
+
+	msg := []byte("mnemonic wallet")
+	sig, err := recovered.SignMsg(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, utils.Verify(msg, signer.PubKey, sig))
+
+	_, err = NewSignerFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon")
+	assert.EqualError(t, err, "invalid mnemonic checksum")
+}
+
+func TestNewSignerFromMnemonic_KnownAnswer(t *testing.T) {
+	// the address goar derives for the BIP39 test mnemonic, a regression check of the full 4096 bits
+	// derivation. It is not yet cross checked with the output of arweave-mnemonic-keys.
+	signer, err := NewSignerFromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about")
+	assert.NoError(t, err)
+	assert.Equal(t, 4096, signer.PubKey.N.BitLen())
+	assert.Equal(t, "l55sI4sCbT9d9AV6WKz2DQpnW4Ld0EcBAZv-CMv_HAQ", signer.Address)
+}
@@ -0,0 +1,134 @@
+package goar
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/permadao/goar/utils"
+)
+
+const (
+	pemPKCS1          = "RSA PRIVATE KEY"
+	pemPKCS8          = "PRIVATE KEY"
+	pemPKCS8Encrypted = "ENCRYPTED PRIVATE KEY"
+)
+
+// NewSignerFromPEM loads a PKCS#1 or PKCS#8 PEM key, passphrase decrypts "ENCRYPTED PRIVATE KEY" (PBES2),
+// it is ignored for unencrypted keys. Legacy "Proc-Type: 4,ENCRYPTED" PKCS#1 keys are rejected,
+// see NewSignerFromLegacyPEM. The key must be a valid arweave wallet key.
+func NewSignerFromPEM(b []byte, passphrase string) (*Signer, error) {
+	return newSignerFromPEM(b, passphrase, false)
+}
+
+// NewSignerFromLegacyPEM as NewSignerFromPEM, it also decrypts legacy "Proc-Type: 4,ENCRYPTED" PKCS#1 keys
+// as written by openssl genrsa -aes256. Their encryption is not authenticated, a wrong passphrase or a
+// tampered key can not always be detected, convert them to PKCS#8 with openssl pkcs8 -topk8 instead.
+func NewSignerFromLegacyPEM(b []byte, passphrase string) (*Signer, error) {
+	return newSignerFromPEM(b, passphrase, true)
+}
+
+func newSignerFromPEM(b []byte, passphrase string, legacy bool) (*Signer, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+	der := block.Bytes
+
+	var prvKey interface{}
+	var err error
+	switch block.Type {
+	case pemPKCS1:
+		//lint:ignore SA1019 legacy pem encryption is only decrypted through NewSignerFromLegacyPEM
+		if x509.IsEncryptedPEMBlock(block) {
+			if !legacy {
+				return nil, errors.New("legacy encrypted pem key, use NewSignerFromLegacyPEM or convert it to PKCS#8")
+			}
+			if passphrase == "" {
+				return nil, errors.New("pem key is encrypted, passphrase required")
+			}
+			//lint:ignore SA1019 see above
+			if der, err = x509.DecryptPEMBlock(block, []byte(passphrase)); err != nil {
+				return nil, err
+			}
+		}
+		prvKey, err = x509.ParsePKCS1PrivateKey(der)
+	case pemPKCS8Encrypted:
+		if passphrase == "" {
+			return nil, errors.New("pem key is encrypted, passphrase required")
+		}
+		if der, err = utils.DecryptPKCS8PrivateKey(der, passphrase); err != nil {
+			return nil, err
+		}
+		prvKey, err = x509.ParsePKCS8PrivateKey(der)
+	case pemPKCS8:
+		prvKey, err = x509.ParsePKCS8PrivateKey(der)
+	default:
+		return nil, fmt.Errorf("unsupported pem type: %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prv, ok := prvKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("prvKey type error")
+	}
+	if err = CheckWalletKey(&prv.PublicKey); err != nil {
+		return nil, err
+	}
+	if err = prv.Validate(); err != nil {
+		return nil, err
+	}
+	prv.Precompute()
+	return NewSignerByPrivateKey(prv), nil
+}
+
+func NewSignerFromPEMPath(path, passphrase string) (*Signer, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewSignerFromPEM(b, passphrase)
+}
+
+// MarshalPKCS1PEM exports the private key as an unencrypted "RSA PRIVATE KEY"
+func (s *Signer) MarshalPKCS1PEM() ([]byte, error) {
+	prvKey, err := s.fullPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemPKCS1, Bytes: x509.MarshalPKCS1PrivateKey(prvKey)}), nil
+}
+
+// MarshalPKCS8PEM exports the private key as "PRIVATE KEY", or as "ENCRYPTED PRIVATE KEY" with
+// PBKDF2-HMAC-SHA256 and AES-256-CBC when passphrase is not empty, readable by openssl pkcs8
+func (s *Signer) MarshalPKCS8PEM(passphrase string) ([]byte, error) {
+	prvKey, err := s.fullPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(prvKey)
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return pem.EncodeToMemory(&pem.Block{Type: pemPKCS8, Bytes: der}), nil
+	}
+	if der, err = utils.EncryptPKCS8PrivateKey(der, passphrase); err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemPKCS8Encrypted, Bytes: der}), nil
+}
+
+// MarshalPublicPEM exports the public key as a PKIX "PUBLIC KEY"
+func (s *Signer) MarshalPublicPEM() ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(s.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
@@ -0,0 +1,232 @@
+package goar
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+)
+
+// remote signer JSON messages
+type signerInfo struct {
+	Owner   string `json:"owner"`
+	Address string `json:"address"`
+}
+
+type signMsgReq struct {
+	Msg string `json:"msg"` // base64url
+}
+
+type signMsgResp struct {
+	Signature string `json:"signature"`
+}
+
+type signTxResp struct {
+	ID        string `json:"id"`
+	Signature string `json:"signature"`
+}
+
+type signerErr struct {
+	Error string `json:"error"`
+}
+
+// RemoteSigner a TxSigner delegating to a signer server, the private key never leaves the server.
+type RemoteSigner struct {
+	client  *http.Client
+	url     string
+	token   string
+	owner   string
+	address string
+}
+
+// NewRemoteSigner connects to a signer server, url is http(s)://host:port or unix:///path/to/socket.
+// token is sent as a bearer token when not empty.
+func NewRemoteSigner(url, token string) (*RemoteSigner, error) {
+	s := &RemoteSigner{
+		client: &http.Client{Timeout: 30 * time.Second},
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+	}
+	if path, ok := strings.CutPrefix(url, "unix://"); ok {
+		s.client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", path)
+			},
+		}
+		s.url = "http://unix"
+	}
+
+	info := signerInfo{}
+	if err := s.call(http.MethodGet, "info", nil, &info); err != nil {
+		return nil, err
+	}
+	if addr, err := utils.OwnerToAddress(info.Owner); err != nil || addr != info.Address {
+		return nil, errors.New("remote signer owner does not match its address")
+	}
+	s.owner = info.Owner
+	s.address = info.Address
+	return s, nil
+}
+
+func (s *RemoteSigner) Owner() string {
+	return s.owner
+}
+
+func (s *RemoteSigner) Address() string {
+	return s.address
+}
+
+// SignTx sends the tx header to the server, format 2 data is not sent as the signature covers its data_root
+func (s *RemoteSigner) SignTx(tx *schema.Transaction) error {
+	if tx.Format == 2 {
+		// computes the data_root locally
+		if _, err := utils.GetSignatureData(tx); err != nil {
+			return err
+		}
+	}
+	req := *tx
+	if req.Format == 2 {
+		req.Data = ""
+		req.DataReader = nil
+	} else if req.DataReader != nil {
+		data, err := io.ReadAll(req.DataReader)
+		if err != nil {
+			return err
+		}
+		req.Data = utils.Base64Encode(data)
+		req.DataReader = nil
+	}
+
+	resp := signTxResp{}
+	if err := s.call(http.MethodPost, "sign_tx", req, &resp); err != nil {
+		return err
+	}
+	tx.ID = resp.ID
+	tx.Signature = resp.Signature
+	return utils.VerifyTransaction(*tx)
+}
+
+func (s *RemoteSigner) SignMsg(msg []byte) ([]byte, error) {
+	resp := signMsgResp{}
+	if err := s.call(http.MethodPost, "sign_msg", signMsgReq{Msg: utils.Base64Encode(msg)}, &resp); err != nil {
+		return nil, err
+	}
+	return utils.Base64Decode(resp.Signature)
+}
+
+func (s *RemoteSigner) call(method, path string, req, resp interface{}) error {
+	var body io.Reader
+	if req != nil {
+		by, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(by)
+	}
+	httpReq, err := http.NewRequest(method, s.url+"/"+path, body)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	httpResp, err := s.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	by, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		e := signerErr{}
+		if json.Unmarshal(by, &e) == nil && e.Error != "" {
+			return fmt.Errorf("remote signer: %s", e.Error)
+		}
+		return fmt.Errorf("remote signer: status %d", httpResp.StatusCode)
+	}
+	return json.Unmarshal(by, resp)
+}
+
+// NewSignerHandler serves signer to RemoteSigner clients, requests must carry the bearer token when it is not empty.
+// Serve it with http.Serve on a tcp or unix listener only reachable by trusted processes.
+func NewSignerHandler(signer TxSigner, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
+		addr, err := TxSignerAddress(signer)
+		if err != nil {
+			writeSignerResp(w, http.StatusInternalServerError, signerErr{Error: err.Error()})
+			return
+		}
+		writeSignerResp(w, http.StatusOK, signerInfo{Owner: signer.Owner(), Address: addr})
+	})
+	mux.HandleFunc("/sign_msg", func(w http.ResponseWriter, r *http.Request) {
+		req := signMsgReq{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeSignerResp(w, http.StatusBadRequest, signerErr{Error: err.Error()})
+			return
+		}
+		msg, err := utils.Base64Decode(req.Msg)
+		if err != nil {
+			writeSignerResp(w, http.StatusBadRequest, signerErr{Error: err.Error()})
+			return
+		}
+		sig, err := signer.SignMsg(msg)
+		if err != nil {
+			writeSignerResp(w, http.StatusInternalServerError, signerErr{Error: err.Error()})
+			return
+		}
+		writeSignerResp(w, http.StatusOK, signMsgResp{Signature: utils.Base64Encode(sig)})
+	})
+	mux.HandleFunc("/sign_tx", func(w http.ResponseWriter, r *http.Request) {
+		tx := &schema.Transaction{}
+		if err := json.NewDecoder(r.Body).Decode(tx); err != nil {
+			writeSignerResp(w, http.StatusBadRequest, signerErr{Error: err.Error()})
+			return
+		}
+		if tx.Owner != signer.Owner() {
+			writeSignerResp(w, http.StatusBadRequest, signerErr{Error: "tx owner is not the signer"})
+			return
+		}
+		if tx.Format == 2 {
+			// the data_root of the header is signed as is
+			tx.Data = ""
+			tx.Chunks = &schema.Chunks{}
+		}
+		if err := signer.SignTx(tx); err != nil {
+			writeSignerResp(w, http.StatusInternalServerError, signerErr{Error: err.Error()})
+			return
+		}
+		writeSignerResp(w, http.StatusOK, signTxResp{ID: tx.ID, Signature: tx.Signature})
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			writeSignerResp(w, http.StatusUnauthorized, signerErr{Error: "unauthorized"})
+			return
+		}
+		if r.URL.Path != "/info" && r.Method != http.MethodPost {
+			writeSignerResp(w, http.StatusMethodNotAllowed, signerErr{Error: "method not allowed"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func writeSignerResp(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
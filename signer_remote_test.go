@@ -0,0 +1,67 @@
+package goar
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	srv := httptest.NewServer(NewSignerHandler(testWallet.Signer, "secret"))
+	defer srv.Close()
+
+	_, err := NewRemoteSigner(srv.URL, "wrong")
+	assert.ErrorContains(t, err, "unauthorized")
+
+	signer, err := NewRemoteSigner(srv.URL, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, testWallet.Signer.Address, signer.Address())
+	assert.Equal(t, testWallet.Owner(), signer.Owner())
+
+	msg := []byte("remote")
+	sig, err := signer.SignMsg(msg)
+	assert.NoError(t, err)
+	assert.NoError(t, utils.Verify(msg, testWallet.Signer.PubKey, sig))
+
+	tx := &schema.Transaction{
+		Format:   2,
+		LastTx:   utils.Base64Encode(make([]byte, 48)),
+		Owner:    signer.Owner(),
+		Quantity: "0",
+		Data:     utils.Base64Encode(make([]byte, 300*1024)),
+		DataSize: "307200",
+		Reward:   "1000",
+	}
+	assert.NoError(t, signer.SignTx(tx))
+	assert.Nil(t, utils.ValidateTransaction(tx))
+
+	tx.Owner = utils.Base64Encode(make([]byte, 512))
+	assert.ErrorContains(t, signer.SignTx(tx), "tx owner is not the signer")
+}
+
+func TestRemoteSigner_Unix(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "signer.sock")
+	l, err := net.Listen("unix", sock)
+	assert.NoError(t, err)
+	defer l.Close()
+	go http.Serve(l, NewSignerHandler(testWallet.Signer, ""))
+
+	signer, err := NewRemoteSigner("unix://"+sock, "")
+	assert.NoError(t, err)
+
+	// the wallet and the bundler use the remote signer
+	w := NewWalletWithTxSigner(signer, "http://127.0.0.1:1")
+	assert.Equal(t, testWallet.Owner(), w.Owner())
+	b, err := NewBundler(signer)
+	assert.NoError(t, err)
+	assert.Equal(t, schema.ArweaveSignType, b.SignType)
+	item, err := b.CreateAndSignItem([]byte("remote item"), "", "", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, utils.VerifyBundleItem(item))
+}
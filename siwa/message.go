@@ -0,0 +1,224 @@
+package siwa
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/permadao/goar/utils"
+)
+
+const (
+	Version = "1"
+
+	header = " wants you to sign in with your Arweave wallet:"
+)
+
+// Message a Sign-In with Arweave message, its text form is what the wallet signs:
+//
+//	example.com wants you to sign in with your Arweave wallet:
+//	<address>
+//
+//	<statement>
+//
+//	Version: 1
+//	Nonce: <nonce>
+//	Issued At: 2024-01-02T15:04:05Z
+//	Expiration Time: 2024-01-02T15:09:05Z
+//	Resources:
+//	- https://example.com/api
+//
+// The address is an arweave address, or a 0x ethereum address for ethereum wallets.
+// The statement, the expiration time and the resources are optional.
+type Message struct {
+	Domain         string
+	Address        string
+	Statement      string
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime time.Time
+	Resources      []string
+}
+
+// NewMessage a message issued now with a random nonce, it expires after ttl when ttl is not 0
+func NewMessage(domain, address string, ttl time.Duration, resources ...string) (*Message, error) {
+	nonce, err := GenerateNonce()
+	if err != nil {
+		return nil, err
+	}
+	m := &Message{
+		Domain:    domain,
+		Address:   address,
+		Nonce:     nonce,
+		IssuedAt:  time.Now().UTC().Truncate(time.Second),
+		Resources: resources,
+	}
+	if ttl > 0 {
+		m.ExpirationTime = m.IssuedAt.Add(ttl)
+	}
+	return m, m.Validate()
+}
+
+// GenerateNonce a 128 bits random nonce
+func GenerateNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return utils.Base64Encode(b), nil
+}
+
+// Validate checks the fields can be written and parsed back as text
+func (m *Message) Validate() error {
+	if m.Domain == "" || strings.ContainsAny(m.Domain, " \n") {
+		return fmt.Errorf("invalid domain: %q", m.Domain)
+	}
+	if !isArAddress(m.Address) && !isEthAddress(m.Address) {
+		return fmt.Errorf("invalid address: %q", m.Address)
+	}
+	if strings.Contains(m.Statement, "\n") {
+		return errors.New("statement must be a single line")
+	}
+	if len(m.Nonce) < 8 || strings.ContainsAny(m.Nonce, " \n") {
+		return fmt.Errorf("invalid nonce: %q", m.Nonce)
+	}
+	if m.IssuedAt.IsZero() {
+		return errors.New("issued at is required")
+	}
+	if !m.ExpirationTime.IsZero() && !m.ExpirationTime.After(m.IssuedAt) {
+		return errors.New("expiration time must be after issued at")
+	}
+	for _, r := range m.Resources {
+		if r == "" || strings.Contains(r, "\n") {
+			return fmt.Errorf("invalid resource: %q", r)
+		}
+	}
+	return nil
+}
+
+// String the canonical text of the message, times are written in UTC with second precision
+func (m *Message) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(m.Domain + header + "\n")
+	sb.WriteString(m.Address + "\n\n")
+	if m.Statement != "" {
+		sb.WriteString(m.Statement + "\n\n")
+	}
+	sb.WriteString("Version: " + Version + "\n")
+	sb.WriteString("Nonce: " + m.Nonce + "\n")
+	sb.WriteString("Issued At: " + formatTime(m.IssuedAt))
+	if !m.ExpirationTime.IsZero() {
+		sb.WriteString("\nExpiration Time: " + formatTime(m.ExpirationTime))
+	}
+	if len(m.Resources) > 0 {
+		sb.WriteString("\nResources:")
+		for _, r := range m.Resources {
+			sb.WriteString("\n- " + r)
+		}
+	}
+	return sb.String()
+}
+
+// ParseMessage parses the canonical text of a message, it fails on any other form
+func ParseMessage(text string) (*Message, error) {
+	lines := strings.Split(text, "\n")
+	next := func() (string, bool) {
+		if len(lines) == 0 {
+			return "", false
+		}
+		l := lines[0]
+		lines = lines[1:]
+		return l, true
+	}
+	field := func(name string) (string, error) {
+		l, ok := next()
+		if v, found := strings.CutPrefix(l, name+": "); ok && found {
+			return v, nil
+		}
+		return "", fmt.Errorf("invalid message: %s expected", name)
+	}
+
+	m := &Message{}
+	l, _ := next()
+	domain, ok := strings.CutSuffix(l, header)
+	if !ok {
+		return nil, errors.New("invalid message: header expected")
+	}
+	m.Domain = domain
+	m.Address, _ = next()
+	if l, ok = next(); !ok || l != "" {
+		return nil, errors.New("invalid message: empty line expected after the address")
+	}
+	if len(lines) > 0 && !strings.HasPrefix(lines[0], "Version: ") {
+		m.Statement, _ = next()
+		if l, ok = next(); !ok || l != "" {
+			return nil, errors.New("invalid message: empty line expected after the statement")
+		}
+	}
+
+	version, err := field("Version")
+	if err != nil {
+		return nil, err
+	}
+	if version != Version {
+		return nil, fmt.Errorf("unsupported message version: %s", version)
+	}
+	if m.Nonce, err = field("Nonce"); err != nil {
+		return nil, err
+	}
+	issuedAt, err := field("Issued At")
+	if err != nil {
+		return nil, err
+	}
+	if m.IssuedAt, err = time.Parse(time.RFC3339, issuedAt); err != nil {
+		return nil, err
+	}
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "Expiration Time: ") {
+		expiration, _ := field("Expiration Time")
+		if m.ExpirationTime, err = time.Parse(time.RFC3339, expiration); err != nil {
+			return nil, err
+		}
+	}
+	if len(lines) > 0 && lines[0] == "Resources:" {
+		next()
+		for len(lines) > 0 {
+			l, _ = next()
+			r, ok := strings.CutPrefix(l, "- ")
+			if !ok {
+				return nil, errors.New("invalid message: resource expected")
+			}
+			m.Resources = append(m.Resources, r)
+		}
+		if len(m.Resources) == 0 {
+			return nil, errors.New("invalid message: resource expected")
+		}
+	}
+	if len(lines) > 0 {
+		return nil, errors.New("invalid message: unexpected trailing lines")
+	}
+
+	if err = m.Validate(); err != nil {
+		return nil, err
+	}
+	// only the canonical form is accepted, the signed text must be the text of the message
+	if m.String() != text {
+		return nil, errors.New("invalid message: not in canonical form")
+	}
+	return m, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func isArAddress(addr string) bool {
+	b, err := utils.Base64Decode(addr)
+	return err == nil && len(b) == 32 && utils.Base64Encode(b) == addr
+}
+
+func isEthAddress(addr string) bool {
+	return strings.HasPrefix(addr, "0x") && common.IsHexAddress(addr)
+}
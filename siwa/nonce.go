@@ -0,0 +1,49 @@
+package siwa
+
+import (
+	"sync"
+	"time"
+)
+
+// NonceStore keeps the nonces issued by a Verifier, implement it on a shared database
+// when several servers verify sign-ins
+type NonceStore interface {
+	// Put records an issued nonce, it can be forgotten after expiration
+	Put(nonce string, expiration time.Time) error
+	// Use removes the nonce atomically, false when it was not issued, is expired or was already used
+	Use(nonce string) (bool, error)
+}
+
+// MemoryNonceStore a NonceStore for a single process
+type MemoryNonceStore struct {
+	lock   sync.Mutex
+	nonces map[string]time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: make(map[string]time.Time)}
+}
+
+func (s *MemoryNonceStore) Put(nonce string, expiration time.Time) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := time.Now()
+	for n, exp := range s.nonces {
+		if !now.Before(exp) {
+			delete(s.nonces, n)
+		}
+	}
+	s.nonces[nonce] = expiration
+	return nil
+}
+
+func (s *MemoryNonceStore) Use(nonce string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	exp, ok := s.nonces[nonce]
+	if !ok {
+		return false, nil
+	}
+	delete(s.nonces, nonce)
+	return time.Now().Before(exp), nil
+}
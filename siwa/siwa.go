@@ -0,0 +1,158 @@
+// Package siwa implements Sign-In with Arweave: the server sends a challenge message with a nonce,
+// the wallet signs its text and the server verifies the signature, the validity period and that
+// the nonce was issued by it and is used once.
+package siwa
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/everFinance/goether"
+	"github.com/permadao/goar"
+	"github.com/permadao/goar/utils"
+)
+
+var (
+	ErrDomainMismatch   = errors.New("siwa: message domain mismatch")
+	ErrNotYetValid      = errors.New("siwa: message issued in the future")
+	ErrExpired          = errors.New("siwa: message expired")
+	ErrInvalidNonce     = errors.New("siwa: nonce not issued or already used")
+	ErrInvalidSignature = errors.New("siwa: invalid signature")
+)
+
+// SignedMessage sent by the client to sign in
+type SignedMessage struct {
+	Message   string `json:"message"`
+	Signature string `json:"signature"`       // base64url
+	Owner     string `json:"owner,omitempty"` // public key of arweave wallets, it can be looked up by the verifier
+}
+
+// Sign signs the text of m with a *goar.Signer, as ArConnect signMessage, or with a *goether.Signer,
+// as personal_sign. The signer must be the wallet of the message address.
+func Sign(m *Message, signer interface{}) (*SignedMessage, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	text := m.String()
+	switch s := signer.(type) {
+	case *goar.Signer:
+		if s.Address != m.Address {
+			return nil, errors.New("signer is not the message address")
+		}
+		sig, err := s.SignMessage([]byte(text))
+		if err != nil {
+			return nil, err
+		}
+		return &SignedMessage{Message: text, Signature: utils.Base64Encode(sig), Owner: s.Owner()}, nil
+	case *goether.Signer:
+		if !isEthAddress(m.Address) || common.HexToAddress(m.Address) != s.Address {
+			return nil, errors.New("signer is not the message address")
+		}
+		sig, err := s.SignMsg([]byte(text))
+		if err != nil {
+			return nil, err
+		}
+		return &SignedMessage{Message: text, Signature: utils.Base64Encode(sig)}, nil
+	default:
+		return nil, errors.New("not support this signer")
+	}
+}
+
+// Verifier issues challenges and verifies sign-ins for a domain
+type Verifier struct {
+	Domain string
+	Store  NonceStore
+	// Client looks up the owner of arweave addresses when sign-ins come without it, optional
+	Client *goar.Client
+	// ClockSkew tolerated between the clocks of the clients and the server
+	ClockSkew time.Duration
+}
+
+func NewVerifier(domain string, store NonceStore) *Verifier {
+	return &Verifier{
+		Domain:    domain,
+		Store:     store,
+		ClockSkew: time.Minute,
+	}
+}
+
+// Challenge creates the message to sign for address, its nonce is valid until the message expires
+func (v *Verifier) Challenge(address string, ttl time.Duration, resources ...string) (*Message, error) {
+	if ttl <= 0 {
+		return nil, errors.New("challenge ttl is required")
+	}
+	m, err := NewMessage(v.Domain, address, ttl, resources...)
+	if err != nil {
+		return nil, err
+	}
+	if err = v.Store.Put(m.Nonce, m.ExpirationTime); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Verify returns the message of a valid sign-in, the nonce is consumed so the sign-in can not be replayed
+func (v *Verifier) Verify(sm *SignedMessage) (*Message, error) {
+	m, err := ParseMessage(sm.Message)
+	if err != nil {
+		return nil, err
+	}
+	if m.Domain != v.Domain {
+		return nil, ErrDomainMismatch
+	}
+	now := time.Now()
+	if m.IssuedAt.After(now.Add(v.ClockSkew)) {
+		return nil, ErrNotYetValid
+	}
+	if !m.ExpirationTime.IsZero() && !now.Before(m.ExpirationTime.Add(v.ClockSkew)) {
+		return nil, ErrExpired
+	}
+	sig, err := utils.Base64Decode(sm.Signature)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	if err = v.verifySignature(m, sig, sm.Owner); err != nil {
+		return nil, err
+	}
+
+	// the nonce is only consumed by valid signatures, others can not burn it
+	ok, err := v.Store.Use(m.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidNonce
+	}
+	return m, nil
+}
+
+func (v *Verifier) verifySignature(m *Message, sig []byte, owner string) error {
+	text := []byte(m.String())
+	if isEthAddress(m.Address) {
+		_, addr, err := goether.Ecrecover(accounts.TextHash(text), sig)
+		if err != nil || addr != common.HexToAddress(m.Address) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+
+	if owner == "" {
+		if v.Client == nil {
+			return errors.New("siwa: owner is required")
+		}
+		var err error
+		if owner, err = v.Client.GetOwnerByAddress(m.Address); err != nil {
+			return fmt.Errorf("siwa: owner lookup: %w", err)
+		}
+	}
+	if addr, err := utils.OwnerToAddress(owner); err != nil || addr != m.Address {
+		return errors.New("siwa: owner does not match the address")
+	}
+	if goar.VerifyMessage(text, sig, owner) != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
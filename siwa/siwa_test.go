@@ -0,0 +1,146 @@
+package siwa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/everFinance/goether"
+	"github.com/permadao/goar"
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_String(t *testing.T) {
+	issuedAt := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	m := &Message{
+		Domain:         "example.com",
+		Address:        "uGx-QfBXSwABKxjha-00dI7vvfyqIYblY6Z5L6cyTFM",
+		Statement:      "Sign in to example",
+		Nonce:          "Ho3bDxMhXNrGxh3dTNBwLQ",
+		IssuedAt:       issuedAt,
+		ExpirationTime: issuedAt.Add(5 * time.Minute),
+		Resources:      []string{"https://example.com/api", "ar://tx"},
+	}
+	text := "example.com wants you to sign in with your Arweave wallet:\n" +
+		"uGx-QfBXSwABKxjha-00dI7vvfyqIYblY6Z5L6cyTFM\n\n" +
+		"Sign in to example\n\n" +
+		"Version: 1\n" +
+		"Nonce: Ho3bDxMhXNrGxh3dTNBwLQ\n" +
+		"Issued At: 2024-01-02T15:04:05Z\n" +
+		"Expiration Time: 2024-01-02T15:09:05Z\n" +
+		"Resources:\n" +
+		"- https://example.com/api\n" +
+		"- ar://tx"
+	assert.Equal(t, text, m.String())
+
+	parsed, err := ParseMessage(text)
+	assert.NoError(t, err)
+	assert.Equal(t, m, parsed)
+
+	// optional fields
+	m.Statement, m.ExpirationTime, m.Resources = "", time.Time{}, nil
+	parsed, err = ParseMessage(m.String())
+	assert.NoError(t, err)
+	assert.Equal(t, m, parsed)
+
+	_, err = ParseMessage(text + "\n")
+	assert.Error(t, err)
+	_, err = ParseMessage("evil.com wants you to sign in with your Arweave wallet:\n\n\nVersion: 1")
+	assert.Error(t, err)
+}
+
+func TestVerifier_Arweave(t *testing.T) {
+	signer, err := goar.NewSignerFromPath("../testKey.json")
+	assert.NoError(t, err)
+	v := NewVerifier("example.com", NewMemoryNonceStore())
+
+	m, err := v.Challenge(signer.Address, time.Minute, "https://example.com/api")
+	assert.NoError(t, err)
+	sm, err := Sign(m, signer)
+	assert.NoError(t, err)
+
+	verified, err := v.Verify(sm)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address, verified.Address)
+	assert.Equal(t, []string{"https://example.com/api"}, verified.Resources)
+
+	// replay
+	_, err = v.Verify(sm)
+	assert.ErrorIs(t, err, ErrInvalidNonce)
+
+	// the owner is required without a client
+	m, err = v.Challenge(signer.Address, time.Minute)
+	assert.NoError(t, err)
+	sm, err = Sign(m, signer)
+	assert.NoError(t, err)
+	owner := sm.Owner
+	sm.Owner = ""
+	_, err = v.Verify(sm)
+	assert.EqualError(t, err, "siwa: owner is required")
+
+	// an invalid signature does not consume the nonce
+	sm.Owner = owner
+	signature := sm.Signature
+	sm.Signature = utils.Base64Encode(make([]byte, 512))
+	_, err = v.Verify(sm)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	sm.Signature = signature
+	_, err = v.Verify(sm)
+	assert.NoError(t, err)
+
+	// a nonce not issued by the verifier
+	m, err = NewMessage("example.com", signer.Address, time.Minute)
+	assert.NoError(t, err)
+	sm, err = Sign(m, signer)
+	assert.NoError(t, err)
+	_, err = v.Verify(sm)
+	assert.ErrorIs(t, err, ErrInvalidNonce)
+
+	other := NewVerifier("other.com", NewMemoryNonceStore())
+	_, err = other.Verify(sm)
+	assert.ErrorIs(t, err, ErrDomainMismatch)
+}
+
+func TestVerifier_Expired(t *testing.T) {
+	signer, err := goar.NewSignerFromPath("../testKey.json")
+	assert.NoError(t, err)
+	v := NewVerifier("example.com", NewMemoryNonceStore())
+	v.ClockSkew = 0
+
+	m, err := v.Challenge(signer.Address, time.Minute)
+	assert.NoError(t, err)
+	m.IssuedAt = m.IssuedAt.Add(-2 * time.Minute)
+	m.ExpirationTime = m.ExpirationTime.Add(-2 * time.Minute)
+	sm, err := Sign(m, signer)
+	assert.NoError(t, err)
+	_, err = v.Verify(sm)
+	assert.ErrorIs(t, err, ErrExpired)
+
+	m.IssuedAt = m.IssuedAt.Add(time.Hour)
+	m.ExpirationTime = m.ExpirationTime.Add(time.Hour)
+	sm, err = Sign(m, signer)
+	assert.NoError(t, err)
+	_, err = v.Verify(sm)
+	assert.ErrorIs(t, err, ErrNotYetValid)
+}
+
+func TestVerifier_Ethereum(t *testing.T) {
+	signer, err := goether.NewSigner("1f534ac18009182c07d266fe4a7903c0bcc8a66190f0967b719b2b3974a69c2f")
+	assert.NoError(t, err)
+	v := NewVerifier("example.com", NewMemoryNonceStore())
+
+	m, err := v.Challenge(signer.Address.String(), time.Minute)
+	assert.NoError(t, err)
+	sm, err := Sign(m, signer)
+	assert.NoError(t, err)
+	assert.Empty(t, sm.Owner)
+
+	verified, err := v.Verify(sm)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address.String(), verified.Address)
+
+	arSigner, err := goar.NewSignerFromPath("../testKey.json")
+	assert.NoError(t, err)
+	_, err = Sign(m, arSigner)
+	assert.EqualError(t, err, "signer is not the message address")
+}
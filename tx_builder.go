@@ -0,0 +1,191 @@
+package goar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+)
+
+// OfflineTx a format 2 tx with the path of its data, it is exported as JSON to be signed
+// and submitted on other machines. The data is not part of the export when referenced by path.
+type OfflineTx struct {
+	Tx       *schema.Transaction `json:"tx"`
+	DataPath string              `json:"dataPath,omitempty"`
+}
+
+// TxBuilder builds unsigned format 2 txs
+type TxBuilder struct {
+	data     []byte
+	dataPath string
+	tags     []schema.Tag
+	target   string
+	quantity Amount
+	reward   *Amount
+	anchor   string
+}
+
+func NewTxBuilder() *TxBuilder {
+	return &TxBuilder{}
+}
+
+// Data attaches data inline, it is exported with the tx
+func (b *TxBuilder) Data(data []byte) *TxBuilder {
+	b.data = data
+	b.dataPath = ""
+	return b
+}
+
+// DataFile references the data by path, it is read again when the tx is submitted
+func (b *TxBuilder) DataFile(path string) *TxBuilder {
+	b.dataPath = path
+	b.data = nil
+	return b
+}
+
+func (b *TxBuilder) Tags(tags ...schema.Tag) *TxBuilder {
+	b.tags = append(b.tags, tags...)
+	return b
+}
+
+func (b *TxBuilder) Target(target string) *TxBuilder {
+	b.target = target
+	return b
+}
+
+func (b *TxBuilder) Quantity(quantity Amount) *TxBuilder {
+	b.quantity = quantity
+	return b
+}
+
+// Reward sets the reward, otherwise the network price is used
+func (b *TxBuilder) Reward(reward Amount) *TxBuilder {
+	b.reward = &reward
+	return b
+}
+
+// Anchor sets last_tx, otherwise the current tx anchor is used, which expires after 50 blocks.
+// Use the last tx id of the wallet when the tx is signed much later.
+func (b *TxBuilder) Anchor(anchor string) *TxBuilder {
+	b.anchor = anchor
+	return b
+}
+
+// Build returns the unsigned tx, with its data_root computed. client fetches the reward
+// and the anchor when they are not set, it can be nil otherwise.
+func (b *TxBuilder) Build(client *Client) (*OfflineTx, error) {
+	tx := &schema.Transaction{
+		Format:   2,
+		Target:   b.target,
+		Quantity: b.quantity.Winston().String(),
+		Tags:     utils.TagsEncode(b.tags),
+		LastTx:   b.anchor,
+	}
+	if b.quantity.Sign() > 0 && b.target == "" {
+		return nil, errors.New("target is required to send quantity")
+	}
+
+	size := len(b.data)
+	if b.dataPath != "" {
+		f, err := os.Open(b.dataPath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		size = int(info.Size())
+		if err = utils.PrepareChunks(tx, f, size); err != nil {
+			return nil, err
+		}
+	} else {
+		tx.Data = utils.Base64Encode(b.data)
+		if err := utils.PrepareChunks(tx, b.data, size); err != nil {
+			return nil, err
+		}
+	}
+	tx.DataSize = strconv.Itoa(size)
+
+	if (b.reward == nil || b.anchor == "") && client == nil {
+		return nil, errors.New("client is required to fetch the reward and anchor")
+	}
+	if b.reward != nil {
+		tx.Reward = b.reward.Winston().String()
+	} else {
+		var target *string
+		if b.target != "" {
+			target = &b.target
+		}
+		reward, err := client.GetTransactionPriceWinston(size, target)
+		if err != nil {
+			return nil, err
+		}
+		tx.Reward = reward.String()
+	}
+	if b.anchor == "" {
+		anchor, err := client.GetTransactionAnchor()
+		if err != nil {
+			return nil, err
+		}
+		tx.LastTx = anchor
+	}
+	return &OfflineTx{Tx: tx, DataPath: b.dataPath}, nil
+}
+
+// Sign sets the owner and signs the tx, it needs neither the network nor the data referenced by path
+func (o *OfflineTx) Sign(signer TxSigner) error {
+	o.Tx.Owner = signer.Owner()
+	return signer.SignTx(o.Tx)
+}
+
+// SubmitOfflineTx re-attaches the data of a signed tx, checks it against data_size and data_root,
+// validates the tx and uploads it with its chunks.
+func (c *Client) SubmitOfflineTx(o *OfflineTx) error {
+	tx := *o.Tx
+	tx.Chunks = nil
+	var data interface{}
+	if o.DataPath != "" {
+		f, err := os.Open(o.DataPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		tx.DataReader = f
+		data = f
+	} else {
+		by, err := utils.Base64Decode(tx.Data)
+		if err != nil {
+			return err
+		}
+		if len(by) == 0 && tx.DataSize != "0" {
+			return fmt.Errorf("data of tx %s is not attached", tx.ID)
+		}
+		data = by
+	}
+
+	if errs := utils.ValidateTransaction(&tx); len(errs) > 0 {
+		return fmt.Errorf("invalid tx %s: %w", tx.ID, errors.Join(errs...))
+	}
+	dataSize, err := strconv.Atoi(tx.DataSize)
+	if err != nil {
+		return err
+	}
+	dataRoot := tx.DataRoot
+	if err = utils.PrepareChunks(&tx, data, dataSize); err != nil {
+		return err
+	}
+	if tx.DataRoot != dataRoot {
+		return errors.New("data mismatch: data_root does not match the attached data")
+	}
+
+	uploader, err := CreateUploader(c, &tx, nil)
+	if err != nil {
+		return err
+	}
+	return uploader.Once()
+}
@@ -0,0 +1,86 @@
+package goar
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxBuilder_Offline(t *testing.T) {
+	anchor := utils.Base64Encode(make([]byte, 48))
+	node := &testTxNode{
+		statuses: map[string]*schema.TxStatus{},
+		anchors:  map[string]string{},
+		anchor:   anchor,
+	}
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+	client := NewClient(srv.URL)
+
+	data := make([]byte, 600*1024)
+	data[1] = 1
+	path := filepath.Join(t.TempDir(), "data.bin")
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+
+	// online: build with the network price and anchor
+	quantity, _ := ParseAR("0.5")
+	unsigned, err := NewTxBuilder().
+		DataFile(path).
+		Tags(schema.Tag{Name: "Content-Type", Value: "application/octet-stream"}).
+		Target(utils.Base64Encode(make([]byte, 32))).
+		Quantity(quantity).
+		Build(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "1000", unsigned.Tx.Reward)
+	assert.Equal(t, anchor, unsigned.Tx.LastTx)
+	assert.Equal(t, "614400", unsigned.Tx.DataSize)
+	assert.Equal(t, "500000000000", unsigned.Tx.Quantity)
+	exported, err := json.Marshal(unsigned)
+	assert.NoError(t, err)
+
+	// air-gapped: sign without data nor network
+	offline := &OfflineTx{}
+	assert.NoError(t, json.Unmarshal(exported, offline))
+	offline.DataPath = filepath.Join(t.TempDir(), "missing.bin")
+	assert.NoError(t, offline.Sign(testWallet.Signer))
+	offline.DataPath = path
+	signed, err := json.Marshal(offline)
+	assert.NoError(t, err)
+
+	// submit elsewhere, the data is read again from the path
+	submit := &OfflineTx{}
+	assert.NoError(t, json.Unmarshal(signed, submit))
+	assert.NoError(t, client.SubmitOfflineTx(submit))
+	assert.Len(t, node.submitted, 1)
+	assert.Equal(t, submit.Tx.ID, node.submitted[0].ID)
+	assert.Empty(t, node.submitted[0].Data)
+
+	// the data changed after signing
+	data[2] = 1
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+	err = client.SubmitOfflineTx(submit)
+	assert.ErrorContains(t, err, "data_root")
+	assert.Len(t, node.submitted, 1)
+}
+
+func TestTxBuilder_Inline(t *testing.T) {
+	_, err := NewTxBuilder().Data([]byte("hello")).Build(nil)
+	assert.Error(t, err)
+
+	reward, _ := ParseWinston("1234")
+	anchor := utils.Base64Encode(make([]byte, 32))
+	unsigned, err := NewTxBuilder().Data([]byte("hello")).Reward(reward).Anchor(anchor).Build(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, unsigned.Sign(testWallet.Signer))
+	assert.Nil(t, utils.ValidateTransaction(unsigned.Tx))
+	assert.Equal(t, utils.Base64Encode([]byte("hello")), unsigned.Tx.Data)
+
+	_, err = NewTxBuilder().Quantity(reward).Reward(reward).Anchor(anchor).Build(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// aptos bundle items as signed by arbundles AptosSigner and MultiSignatureAptosSigner
+
+const (
+	aptosMultiMaxKeys    = 32
+	aptosMultiBitmapSize = 4
+
+	// aptos authentication key schemes
+	aptosEd25519Scheme      = 0x00
+	aptosMultiEd25519Scheme = 0x01
+)
+
+// AptosMessage the full message signed by aptos wallets for msg, with the nonce used by arbundles
+func AptosMessage(msg []byte) []byte {
+	return []byte("APTOS\nmessage: " + hex.EncodeToString(msg) + "\nnonce: bundlr")
+}
+
+// AptosAddress the address of an ed25519 aptos account
+func AptosAddress(pubKey []byte) string {
+	return aptosAuthKey(pubKey, aptosEd25519Scheme)
+}
+
+// AptosMultiAddress the address of the multi-ed25519 account of a MultiAptos owner
+func AptosMultiAddress(owner []byte) (string, error) {
+	keys, threshold, err := decodeAptosMultiOwner(owner)
+	if err != nil {
+		return "", err
+	}
+	return aptosAuthKey(append(bytes.Join(keys, nil), byte(threshold)), aptosMultiEd25519Scheme), nil
+}
+
+// NewAptosMultiOwner the MultiAptos owner of a multi-ed25519 account
+func NewAptosMultiOwner(pubKeys [][]byte, threshold int) ([]byte, error) {
+	if len(pubKeys) == 0 || len(pubKeys) > aptosMultiMaxKeys {
+		return nil, fmt.Errorf("multi aptos requires 1 to %d keys, got %d", aptosMultiMaxKeys, len(pubKeys))
+	}
+	if threshold < 1 || threshold > len(pubKeys) {
+		return nil, fmt.Errorf("invalid multi aptos threshold: %d", threshold)
+	}
+	owner := make([]byte, aptosMultiMaxKeys*ed25519.PublicKeySize+1)
+	for i, k := range pubKeys {
+		if len(k) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 public key length: %d", len(k))
+		}
+		copy(owner[i*ed25519.PublicKeySize:], k)
+	}
+	owner[len(owner)-1] = byte(threshold)
+	return owner, nil
+}
+
+// NewAptosMultiSignature the MultiAptos signature of the signatures by key index of the owner
+func NewAptosMultiSignature(signatures map[int][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("no signature")
+	}
+	sig := make([]byte, aptosMultiMaxKeys*ed25519.SignatureSize+aptosMultiBitmapSize)
+	bitmap := sig[aptosMultiMaxKeys*ed25519.SignatureSize:]
+	// as arbundles, the signature of key i is in slot i, the bitmap bits from the most significant bit of its first byte
+	for i, s := range signatures {
+		if i < 0 || i >= aptosMultiMaxKeys {
+			return nil, errors.New("signature key index out of range")
+		}
+		if len(s) != ed25519.SignatureSize {
+			return nil, fmt.Errorf("invalid ed25519 signature length: %d", len(s))
+		}
+		copy(sig[i*ed25519.SignatureSize:], s)
+		bitmap[i/8] |= 0x80 >> (i % 8)
+	}
+	return sig, nil
+}
+
+// VerifyAptosMulti checks the bitmap has at least threshold keys and the signature of each of these keys
+func VerifyAptosMulti(owner, msg, sig []byte) error {
+	keys, threshold, err := decodeAptosMultiOwner(owner)
+	if err != nil {
+		return err
+	}
+	if len(sig) != aptosMultiMaxKeys*ed25519.SignatureSize+aptosMultiBitmapSize {
+		return fmt.Errorf("invalid multi aptos signature length: %d", len(sig))
+	}
+	bitmap := sig[aptosMultiMaxKeys*ed25519.SignatureSize:]
+	signers := 0
+	for _, b := range bitmap {
+		signers += bits.OnesCount8(b)
+	}
+	if signers < threshold {
+		return fmt.Errorf("multi aptos signature has %d signers, threshold %d", signers, threshold)
+	}
+
+	for i := 0; i < aptosMultiMaxKeys; i++ {
+		if bitmap[i/8]&(0x80>>(i%8)) == 0 {
+			continue
+		}
+		if i >= len(keys) {
+			return fmt.Errorf("multi aptos bitmap references key %d of %d", i, len(keys))
+		}
+		if !ed25519.Verify(keys[i], msg, sig[i*ed25519.SignatureSize:(i+1)*ed25519.SignatureSize]) {
+			return fmt.Errorf("verify multi aptos signature of key %d failed", i)
+		}
+	}
+	return nil
+}
+
+// the keys are the non zero keys before the padding
+func decodeAptosMultiOwner(owner []byte) ([][]byte, int, error) {
+	if len(owner) != aptosMultiMaxKeys*ed25519.PublicKeySize+1 {
+		return nil, 0, fmt.Errorf("invalid multi aptos owner length: %d", len(owner))
+	}
+	keys := make([][]byte, 0)
+	zero := make([]byte, ed25519.PublicKeySize)
+	for i := 0; i < aptosMultiMaxKeys; i++ {
+		k := owner[i*ed25519.PublicKeySize : (i+1)*ed25519.PublicKeySize]
+		if bytes.Equal(k, zero) {
+			break
+		}
+		keys = append(keys, k)
+	}
+	threshold := int(owner[len(owner)-1])
+	if len(keys) == 0 || threshold < 1 || threshold > len(keys) {
+		return nil, 0, fmt.Errorf("invalid multi aptos threshold: %d of %d keys", threshold, len(keys))
+	}
+	return keys, threshold, nil
+}
+
+func aptosAuthKey(pubKey []byte, scheme byte) string {
+	h := sha3.New256()
+	h.Write(pubKey)
+	h.Write([]byte{scheme})
+	return "0x" + hex.EncodeToString(h.Sum(nil))
+}
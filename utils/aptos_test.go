@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/permadao/goar/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAptosMessage(t *testing.T) {
+	assert.Equal(t, "APTOS\nmessage: 00ff\nnonce: bundlr", string(AptosMessage([]byte{0x00, 0xff})))
+}
+
+func TestVerifyAptosMulti(t *testing.T) {
+	pubKeys := make([][]byte, 3)
+	prvKeys := make([]ed25519.PrivateKey, 3)
+	for i := range pubKeys {
+		pubKeys[i], prvKeys[i], _ = ed25519.GenerateKey(rand.Reader)
+	}
+	owner, err := NewAptosMultiOwner(pubKeys, 2)
+	assert.NoError(t, err)
+	assert.Len(t, owner, schema.SigConfigMap[schema.MultiAptosSignType].PubLength)
+	msg := []byte("multi aptos")
+
+	sig, err := NewAptosMultiSignature(map[int][]byte{
+		0: ed25519.Sign(prvKeys[0], msg),
+		2: ed25519.Sign(prvKeys[2], msg),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sig, schema.SigConfigMap[schema.MultiAptosSignType].SigLength)
+	assert.Equal(t, []byte{0xa0, 0, 0, 0}, sig[len(sig)-4:])
+	// the signature of a key is in the slot of its index, slot 1 stays empty
+	assert.Equal(t, ed25519.Sign(prvKeys[2], msg), sig[2*64:3*64])
+	assert.Equal(t, make([]byte, 64), sig[64:2*64])
+	assert.NoError(t, VerifyAptosMulti(owner, msg, sig))
+	assert.Error(t, VerifyAptosMulti(owner, []byte("other"), sig))
+
+	// below threshold
+	sig, err = NewAptosMultiSignature(map[int][]byte{1: ed25519.Sign(prvKeys[1], msg)})
+	assert.NoError(t, err)
+	assert.EqualError(t, VerifyAptosMulti(owner, msg, sig), "multi aptos signature has 1 signers, threshold 2")
+
+	// signature of the wrong key
+	sig, err = NewAptosMultiSignature(map[int][]byte{0: ed25519.Sign(prvKeys[0], msg), 1: ed25519.Sign(prvKeys[2], msg)})
+	assert.NoError(t, err)
+	assert.EqualError(t, VerifyAptosMulti(owner, msg, sig), "verify multi aptos signature of key 1 failed")
+
+	// bitmap out of the keys
+	sig, err = NewAptosMultiSignature(map[int][]byte{0: ed25519.Sign(prvKeys[0], msg), 5: ed25519.Sign(prvKeys[1], msg)})
+	assert.NoError(t, err)
+	assert.EqualError(t, VerifyAptosMulti(owner, msg, sig), "multi aptos bitmap references key 5 of 3")
+
+	_, err = NewAptosMultiOwner(pubKeys, 4)
+	assert.Error(t, err)
+}
+
+func TestAptosAddress(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	addr := AptosAddress(pub)
+	assert.Len(t, addr, 66)
+	assert.Equal(t, "0x", addr[:2])
+
+	owner, err := NewAptosMultiOwner([][]byte{pub}, 1)
+	assert.NoError(t, err)
+	multiAddr, err := AptosMultiAddress(owner)
+	assert.NoError(t, err)
+	assert.NotEqual(t, addr, multiAddr)
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// BIP39 english wordlist, https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+//
+//go:embed bip39_english.txt
+var bip39English string
+
+var (
+	bip39Words     = strings.Fields(bip39English)
+	bip39WordIndex = func() map[string]int {
+		m := make(map[string]int, len(bip39Words))
+		for i, w := range bip39Words {
+			m[w] = i
+		}
+		return m
+	}()
+)
+
+// GenerateMnemonic returns a new 12 words BIP39 mnemonic, as created by arweave.app and ArConnect
+func GenerateMnemonic() (string, error) {
+	entropy := make([]byte, 16)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return EntropyToMnemonic(entropy)
+}
+
+// EntropyToMnemonic encodes 16 to 32 bytes of entropy as 12 to 24 words
+func EntropyToMnemonic(entropy []byte) (string, error) {
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return "", fmt.Errorf("invalid mnemonic entropy length: %d", len(entropy))
+	}
+	checksumBits := len(entropy) / 4
+	hash := sha256.Sum256(entropy)
+	// entropy bits followed by the first checksumBits of its hash
+	b := new(big.Int).SetBytes(entropy)
+	b.Lsh(b, uint(checksumBits))
+	b.Or(b, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	n := (len(entropy)*8 + checksumBits) / 11
+	words := make([]string, n)
+	mask := big.NewInt(2047)
+	for i := n - 1; i >= 0; i-- {
+		words[i] = bip39Words[new(big.Int).And(b, mask).Int64()]
+		b.Rsh(b, 11)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// ValidateMnemonic checks the words and the checksum of a BIP39 english mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return fmt.Errorf("invalid mnemonic length: %d words", len(words))
+	}
+	b := new(big.Int)
+	for _, w := range words {
+		idx, ok := bip39WordIndex[w]
+		if !ok {
+			return fmt.Errorf("invalid mnemonic word: %s", w)
+		}
+		b.Lsh(b, 11)
+		b.Or(b, big.NewInt(int64(idx)))
+	}
+
+	checksumBits := len(words) / 3
+	checksum := new(big.Int).And(b, big.NewInt(int64(1<<checksumBits-1))).Int64()
+	b.Rsh(b, uint(checksumBits))
+	entropy := b.FillBytes(make([]byte, checksumBits*4))
+	hash := sha256.Sum256(entropy)
+	if int64(hash[0]>>(8-checksumBits)) != checksum {
+		return errors.New("invalid mnemonic checksum")
+	}
+	return nil
+}
+
+// MnemonicToSeed derives the 64 bytes BIP39 seed, passphrase is empty for arweave wallets
+func MnemonicToSeed(mnemonic, passphrase string) []byte {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), 2048, 64, sha512.New)
+}
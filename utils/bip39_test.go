@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntropyToMnemonic(t *testing.T) {
+	// BIP39 test vectors
+	cases := []struct {
+		entropy  string
+		mnemonic string
+	}{
+		{"00000000000000000000000000000000", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"},
+		{"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f", "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+		{"80808080808080808080808080808080", "letter advice cage absurd amount doctor acoustic avoid letter advice cage above"},
+		{"ffffffffffffffffffffffffffffffff", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong"},
+		{"0000000000000000000000000000000000000000000000000000000000000000", strings.Repeat("abandon ", 23) + "art"},
+	}
+	for _, c := range cases {
+		entropy, _ := hex.DecodeString(c.entropy)
+		mnemonic, err := EntropyToMnemonic(entropy)
+		assert.NoError(t, err)
+		assert.Equal(t, c.mnemonic, mnemonic)
+		assert.NoError(t, ValidateMnemonic(mnemonic))
+	}
+
+	_, err := EntropyToMnemonic(make([]byte, 15))
+	assert.Error(t, err)
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	mnemonic, err := GenerateMnemonic()
+	assert.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 12)
+	assert.NoError(t, ValidateMnemonic(mnemonic))
+
+	assert.EqualError(t, ValidateMnemonic(strings.Repeat("abandon ", 12)), "invalid mnemonic checksum")
+	assert.EqualError(t, ValidateMnemonic(strings.Repeat("abandon ", 11)+"goar"), "invalid mnemonic word: goar")
+	assert.Error(t, ValidateMnemonic("abandon about"))
+}
+
+func TestMnemonicToSeed(t *testing.T) {
+	seed := MnemonicToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "TREZOR")
+	assert.Equal(t, "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04", hex.EncodeToString(seed))
+}
@@ -1,9 +1,15 @@
 package utils
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
+	"math/big"
+	"sort"
+	"strconv"
 
 	"github.com/permadao/goar/schema"
 )
@@ -12,12 +18,46 @@ const (
 	height_2_0 = int64(422250)
 	height_2_4 = int64(633720)
 	height_2_5 = int64(812970)
+	height_2_6 = int64(1132210)
+	// double signing proof fork, the signature commits to the cumulative difficulties
+	height_2_6_8 = int64(1189560)
+	height_2_7   = int64(1275480)
+	height_2_8   = int64(1547120)
 )
 
+// GenerateIndepHash returns the indep_hash of b, or an empty hash when it can not be generated,
+// as for arweave 2.8+ blocks which are not supported. Arweave 1.0 blocks return their own indep_hash.
+//
+// Deprecated: use BlockIndepHash, which reports why the hash can not be generated.
 func GenerateIndepHash(b schema.Block) string {
 	if b.Height < height_2_0 { // not support arweave v1.0
 		return b.IndepHash
 	}
+	hash, err := BlockIndepHash(b)
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
+// BlockIndepHash generates the indep_hash of b,
+// blocks of arweave 1.0 and 2.8+ return schema.ErrUnsupportedBlockVersion as their serialization is not supported
+func BlockIndepHash(b schema.Block) (string, error) {
+	if err := checkBlockVersion(b); err != nil {
+		return "", err
+	}
+	if b.Height >= height_2_6 {
+		signedHash, err := GenerateSignedHash(b)
+		if err != nil {
+			return "", err
+		}
+		sig, err := Base64Decode(b.Signature)
+		if err != nil {
+			return "", err
+		}
+		hash := sha512.Sum384(ConcatBuffer(signedHash, sig))
+		return Base64Encode(hash[:]), nil
+	}
 
 	bds := generateBlockDataSegment(b)
 	list := make([]interface{}, 0)
@@ -28,7 +68,26 @@ func GenerateIndepHash(b schema.Block) string {
 		list = append(list, poaToList(b.Poa))
 	}
 	hash := DeepHash(list)
-	return Base64Encode(hash[:])
+	return Base64Encode(hash[:]), nil
+}
+
+// VerifyIndepHash checks the indep_hash of b, see BlockIndepHash for the unsupported blocks
+func VerifyIndepHash(b schema.Block) error {
+	h, err := BlockIndepHash(b)
+	if err != nil {
+		return err
+	}
+	if h != b.IndepHash {
+		return fmt.Errorf("indep_hash mismatch, generated: %s", h)
+	}
+	return nil
+}
+
+func checkBlockVersion(b schema.Block) error {
+	if b.Height < height_2_0 || b.Height >= height_2_8 {
+		return fmt.Errorf("%w: height %d", schema.ErrUnsupportedBlockVersion, b.Height)
+	}
+	return nil
 }
 
 func generateBlockDataSegment(b schema.Block) []byte {
@@ -91,6 +150,248 @@ func generateBlockDataSegmentBase(b schema.Block) []byte {
 	return hash[:]
 }
 
+// GenerateSignedHash returns the hash signed by the miner of a 2.6+ block.
+// Every element is either fixed-size or prefixed with its size, as the node serializes it.
+func GenerateSignedHash(b schema.Block) ([]byte, error) {
+	if b.Height < height_2_6 {
+		return nil, errors.New("signed hash only exists since arweave 2.6")
+	}
+	if err := checkBlockVersion(b); err != nil {
+		return nil, err
+	}
+	if b.NonceLimiterInfo == nil {
+		return nil, errors.New("nonce_limiter_info is required")
+	}
+	if len(b.UsdToArRate) != 2 || len(b.ScheduledUsdToArRate) != 2 {
+		return nil, errors.New("usd_to_ar_rate incorrect")
+	}
+	e := &segmentEncoder{}
+	nli := b.NonceLimiterInfo
+
+	e.bin(b.PreviousBlock, 8)
+	e.int(b.Timestamp, 8)
+	e.bin(b.Nonce, 16)
+	e.int(b.Height, 8)
+	e.int(b.Diff, 16)
+	e.int(b.CumulativeDiff, 16)
+	e.int(b.LastRetarget, 8)
+	e.bin(b.Hash, 8)
+	e.int(b.BlockSize, 16)
+	e.int(b.WeaveSize, 16)
+	if b.RewardAddr == "unclaimed" {
+		e.bin("", 8)
+	} else {
+		e.bin(b.RewardAddr, 8)
+	}
+	e.bin(b.TxRoot, 8)
+	e.bin(b.WalletList, 8)
+	e.bin(b.HashListMerkle, 8)
+	e.int(b.RewardPool, 8)
+	e.int(b.Packing25Threshold, 8)
+	e.int(b.StrictDataSplitThreshold, 8)
+	e.int(b.UsdToArRate[0], 8)
+	e.int(b.UsdToArRate[1], 8)
+	e.int(b.ScheduledUsdToArRate[0], 8)
+	e.int(b.ScheduledUsdToArRate[1], 8)
+	tags := make([]string, 0, len(b.Tags))
+	for _, tag := range b.Tags {
+		tags = append(tags, fmt.Sprint(tag))
+	}
+	e.binList(tags, 16, 16)
+	e.binList(b.Txs, 16, 8)
+	e.int(b.Reward, 8)
+	e.int(b.RecallByte, 16)
+	e.bin(b.HashPreimage, 8)
+	e.int(b.RecallByte2, 16)
+	e.bin(b.RewardKey, 16)
+	e.int(b.PartitionNumber, 8)
+	e.fixedBin(nli.Output, 32)
+	e.uint(nli.GlobalStepNumber, 64)
+	e.fixedBin(nli.Seed, 48)
+	e.fixedBin(nli.NextSeed, 48)
+	e.uint(nli.ZoneUpperBound, 256)
+	e.uint(nli.NextZoneUpperBound, 256)
+	e.bin(nli.PrevOutput, 8)
+	e.fixedBinList(nli.Checkpoints, 32)
+	e.fixedBinList(nli.LastStepCheckpoints, 32)
+	e.bin(b.PreviousSolutionHash, 8)
+	e.int(b.PricePerGibMinute, 8)
+	e.int(b.ScheduledPricePerGibMinute, 8)
+	e.fixedBin(b.RewardHistoryHash, 32)
+	e.int(b.DebtSupply, 8)
+	e.uint(b.KryderPlusRateMultiplier, 24)
+	e.uint(b.KryderPlusRateMultiplierLatch, 8)
+	e.uint(b.Denomination, 24)
+	e.int(b.RedenominationHeight, 8)
+	e.doubleSigningProof(b.DoubleSigningProof)
+	e.int(b.PreviousCumulativeDiff, 16)
+	if b.Height >= height_2_7 {
+		poa2 := schema.POA{}
+		if b.Poa2 != nil {
+			poa2 = *b.Poa2
+		}
+		e.int(b.MerkleRebaseSupportThreshold, 16)
+		e.bin(b.Poa.DataPath, 24)
+		e.bin(b.Poa.TxPath, 24)
+		e.bin(poa2.DataPath, 24)
+		e.bin(poa2.TxPath, 24)
+		e.fixedBin(b.ChunkHash, 32)
+		e.bin(b.Chunk2Hash, 8)
+		e.fixedBin(b.BlockTimeHistoryHash, 32)
+		e.int(nli.VdfDifficulty, 8)
+		e.int(nli.NextVdfDifficulty, 8)
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	hash := sha256.Sum256(e.buf)
+	return hash[:], nil
+}
+
+// VerifyBlockSignature checks the signature of a 2.6+ block against its reward_key,
+// and that the reward_key belongs to the reward_addr.
+func VerifyBlockSignature(b schema.Block) error {
+	signedHash, err := GenerateSignedHash(b)
+	if err != nil {
+		return err
+	}
+	addr, err := OwnerToAddress(b.RewardKey)
+	if err != nil {
+		return err
+	}
+	if addr != b.RewardAddr {
+		return fmt.Errorf("reward_key does not match reward_addr %s", b.RewardAddr)
+	}
+	pubKey, err := OwnerToPubKey(b.RewardKey)
+	if err != nil {
+		return err
+	}
+	sig, err := Base64Decode(b.Signature)
+	if err != nil {
+		return err
+	}
+	msg := signedHash
+	if b.Height >= height_2_6_8 {
+		prevSolutionHash, err := Base64Decode(b.PreviousSolutionHash)
+		if err != nil {
+			return err
+		}
+		e := &segmentEncoder{}
+		e.int(b.CumulativeDiff, 16)
+		e.int(b.PreviousCumulativeDiff, 16)
+		if e.err != nil {
+			return e.err
+		}
+		msg = ConcatBuffer(e.buf, prevSolutionHash, signedHash)
+	}
+	return Verify(msg, pubKey, sig)
+}
+
+// segmentEncoder serializes block fields, the first error is kept in err
+type segmentEncoder struct {
+	buf []byte
+	err error
+}
+
+// int writes an unsigned integer prefixed with its byte length, an empty value is encoded as a zero length
+func (e *segmentEncoder) int(v interface{}, sizeBits int) {
+	if n, ok := v.(*big.Int); ok && n == nil {
+		v = nil
+	}
+	str := fmt.Sprint(v)
+	if v == nil || str == "" {
+		e.buf = append(e.buf, make([]byte, sizeBits/8)...)
+		return
+	}
+	n, ok := new(big.Int).SetString(str, 10)
+	if !ok || n.Sign() < 0 {
+		e.setErr(fmt.Errorf("invalid integer: %s", str))
+		return
+	}
+	by := n.Bytes()
+	if len(by) == 0 {
+		by = []byte{0}
+	}
+	e.buf = append(e.buf, PaddedBigBytes(big.NewInt(int64(len(by))), sizeBits/8)...)
+	e.buf = append(e.buf, by...)
+}
+
+// uint writes a fixed-width big endian unsigned integer
+func (e *segmentEncoder) uint(v interface{}, bits int) {
+	str := fmt.Sprint(v)
+	if str == "" {
+		str = "0"
+	}
+	n, ok := new(big.Int).SetString(str, 10)
+	if !ok || n.Sign() < 0 || n.BitLen() > bits {
+		e.setErr(fmt.Errorf("invalid %d bits integer: %s", bits, str))
+		return
+	}
+	e.buf = append(e.buf, PaddedBigBytes(n, bits/8)...)
+}
+
+// bin writes base64url decoded bytes prefixed with their length
+func (e *segmentEncoder) bin(b64 string, sizeBits int) {
+	by, err := Base64Decode(b64)
+	if err != nil {
+		e.setErr(err)
+		return
+	}
+	e.buf = append(e.buf, PaddedBigBytes(big.NewInt(int64(len(by))), sizeBits/8)...)
+	e.buf = append(e.buf, by...)
+}
+
+func (e *segmentEncoder) fixedBin(b64 string, size int) {
+	by, err := Base64Decode(b64)
+	if err != nil {
+		e.setErr(err)
+		return
+	}
+	if len(by) != size {
+		e.setErr(fmt.Errorf("expect %d bytes, got %d", size, len(by)))
+		return
+	}
+	e.buf = append(e.buf, by...)
+}
+
+// binList the node encodes list elements in reverse order
+func (e *segmentEncoder) binList(list []string, lenBits, elemSizeBits int) {
+	e.buf = append(e.buf, PaddedBigBytes(big.NewInt(int64(len(list))), lenBits/8)...)
+	for i := len(list) - 1; i >= 0; i-- {
+		e.bin(list[i], elemSizeBits)
+	}
+}
+
+func (e *segmentEncoder) fixedBinList(list []string, size int) {
+	e.buf = append(e.buf, PaddedBigBytes(big.NewInt(int64(len(list))), 2)...)
+	for _, elem := range list {
+		e.fixedBin(elem, size)
+	}
+}
+
+func (e *segmentEncoder) doubleSigningProof(p *schema.DoubleSigningProof) {
+	if p == nil || p.PubKey == "" {
+		e.buf = append(e.buf, 0)
+		return
+	}
+	e.buf = append(e.buf, 1)
+	e.fixedBin(p.PubKey, 512)
+	e.fixedBin(p.Sig1, 512)
+	e.int(p.Cdiff1, 16)
+	e.int(p.PrevCdiff1, 16)
+	e.fixedBin(p.Preimage1, 64)
+	e.fixedBin(p.Sig2, 512)
+	e.int(p.Cdiff2, 16)
+	e.int(p.PrevCdiff2, 16)
+	e.fixedBin(p.Preimage2, 64)
+}
+
+func (e *segmentEncoder) setErr(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
 func poaToList(poa schema.POA) []string {
 	return []string{
 		Base64Encode([]byte(poa.Option)),
@@ -100,32 +401,79 @@ func poaToList(poa schema.POA) []string {
 	}
 }
 
-func DecodeBlock(body string) (*schema.Block, error) {
-	b := &schema.Block{}
-	// json unmarshal exist number precision problem
-	decoder := json.NewDecoder(strings.NewReader(body))
-	decoder.UseNumber()
-	err := decoder.Decode(b)
-	if err != nil {
-		return nil, err
+// GenerateTxRoot rebuilds the tx_root of a block from its transactions.
+// Transactions are ordered by id and tagged with their end offset in the block,
+// from 2.5 on every transaction is padded to a chunk boundary with an empty leaf.
+func GenerateTxRoot(txs []*schema.Transaction, height int64) (string, error) {
+	type entry struct {
+		id       []byte
+		dataRoot []byte
+		dataSize int
 	}
-	formatBlockFields(b)
-	return b, err
-}
+	entries := make([]entry, 0, len(txs))
+	for _, tx := range txs {
+		id, err := Base64Decode(tx.ID)
+		if err != nil {
+			return "", err
+		}
+		dataSize, err := strconv.Atoi(tx.DataSize)
+		if err != nil {
+			return "", fmt.Errorf("invalid data_size of tx %s: %v", tx.ID, err)
+		}
+		dataRoot, err := txDataRoot(tx, dataSize)
+		if err != nil {
+			return "", err
+		}
+		entries = append(entries, entry{id: id, dataRoot: dataRoot, dataSize: dataSize})
+	}
+	if len(entries) == 0 {
+		return "", nil
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].id, entries[j].id) < 0
+	})
 
-func formatBlockFields(b *schema.Block) {
-	if _, ok := b.RewardPool.(string); !ok {
-		by, _ := json.Marshal(b.RewardPool)
-		b.RewardPool = string(by)
+	leaves := make([]schema.Chunk, 0, len(entries))
+	pos := 0
+	for _, e := range entries {
+		end := pos + e.dataSize
+		leaves = append(leaves, schema.Chunk{DataHash: e.dataRoot, MinByteRange: pos, MaxByteRange: end})
+		pos = end
+		if height >= height_2_5 && e.dataSize > 0 {
+			padded := ((e.dataSize-1)/schema.MAX_CHUNK_SIZE + 1) * schema.MAX_CHUNK_SIZE
+			if padded > e.dataSize {
+				pos = end + padded - e.dataSize
+				leaves = append(leaves, schema.Chunk{DataHash: []byte{}, MinByteRange: end, MaxByteRange: pos})
+			}
+		}
 	}
+	root := buildLayer(generateLeaves(leaves), 0)
+	return Base64Encode(root.ID), nil
+}
 
-	if _, ok := b.WeaveSize.(string); !ok {
-		by, _ := json.Marshal(b.WeaveSize)
-		b.WeaveSize = string(by)
+func txDataRoot(tx *schema.Transaction, dataSize int) ([]byte, error) {
+	if tx.Format == 2 || dataSize == 0 {
+		return Base64Decode(tx.DataRoot)
+	}
+	// format 1 transactions carry no data_root, it is computed from the data
+	data, err := Base64Decode(tx.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != dataSize {
+		return nil, errors.New("format 1 tx data is required to compute data_root")
+	}
+	chunks, err := GenerateChunks(data)
+	if err != nil {
+		return nil, err
 	}
+	return chunks.DataRoot, nil
+}
 
-	if _, ok := b.BlockSize.(string); !ok {
-		by, _ := json.Marshal(b.BlockSize)
-		b.BlockSize = string(by)
+func DecodeBlock(body string) (*schema.Block, error) {
+	b := &schema.Block{}
+	if err := json.Unmarshal([]byte(body), b); err != nil {
+		return nil, err
 	}
+	return b, nil
 }
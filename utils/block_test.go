@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
 	"testing"
 
 	"github.com/permadao/goar/schema"
@@ -411,3 +415,181 @@ func TestIndepHash(t *testing.T) {
 	indepHash = GenerateIndepHash(*b)
 	assert.Equal(t, "yeQY2CnMaZknfOzeVoUhAxw1U7zSzXY3IkDlYG9_Z4FmNfCW7Gkhk3qxuT2m0lvQ", indepHash)
 }
+
+func TestVerifyBlockSignature(t *testing.T) {
+	bigInt := func(s string) *big.Int {
+		n, _ := new(big.Int).SetString(s, 10)
+		return n
+	}
+	prvKey, err := GenerateRsaKey(4096)
+	assert.NoError(t, err)
+	owner := Base64Encode(prvKey.N.Bytes())
+	addr, err := OwnerToAddress(owner)
+	assert.NoError(t, err)
+
+	hash32 := Base64Encode(make([]byte, 32))
+	hash48 := Base64Encode(make([]byte, 48))
+	b := schema.Block{
+		Nonce:                    Base64Encode([]byte{1}),
+		PreviousBlock:            hash48,
+		Timestamp:                1700000000,
+		LastRetarget:             1699999000,
+		Diff:                     bigInt("115792089237316195423570985008687907853269984665640564039439137263839420088320"),
+		Height:                   1300000,
+		Hash:                     hash32,
+		Txs:                      []string{hash32},
+		TxRoot:                   hash32,
+		WalletList:               hash48,
+		HashListMerkle:           hash48,
+		RewardAddr:               addr,
+		Tags:                     []interface{}{},
+		RewardPool:               bigInt("1000"),
+		WeaveSize:                bigInt("262144"),
+		BlockSize:                bigInt("262144"),
+		CumulativeDiff:           bigInt("12345"),
+		UsdToArRate:              []string{"1", "10"},
+		ScheduledUsdToArRate:     []string{"1", "10"},
+		Packing25Threshold:       "0",
+		StrictDataSplitThreshold: "30607159107830",
+		HashPreimage:             hash32,
+		RecallByte:               "100",
+		Reward:                   "2000",
+		PreviousSolutionHash:     hash32,
+		PartitionNumber:          3,
+		NonceLimiterInfo: &schema.NonceLimiterInfo{
+			Output:              hash32,
+			GlobalStepNumber:    42,
+			Seed:                hash48,
+			NextSeed:            hash48,
+			ZoneUpperBound:      1000,
+			NextZoneUpperBound:  2000,
+			PrevOutput:          hash32,
+			LastStepCheckpoints: []string{hash32},
+			Checkpoints:         []string{hash32},
+			VdfDifficulty:       "600000",
+			NextVdfDifficulty:   "600000",
+		},
+		RewardKey:                     owner,
+		PricePerGibMinute:             "100",
+		ScheduledPricePerGibMinute:    "100",
+		RewardHistoryHash:             hash32,
+		DebtSupply:                    "0",
+		KryderPlusRateMultiplier:      "1",
+		KryderPlusRateMultiplierLatch: "0",
+		Denomination:                  "1",
+		PreviousCumulativeDiff:        "12000",
+		MerkleRebaseSupportThreshold:  "100000",
+		ChunkHash:                     hash32,
+		BlockTimeHistoryHash:          hash32,
+	}
+
+	signedHash, err := GenerateSignedHash(b)
+	assert.NoError(t, err)
+	e := &segmentEncoder{}
+	e.int(b.CumulativeDiff, 16)
+	e.int(b.PreviousCumulativeDiff, 16)
+	prevSolutionHash, _ := Base64Decode(b.PreviousSolutionHash)
+	sig, err := Sign(ConcatBuffer(e.buf, prevSolutionHash, signedHash), prvKey)
+	assert.NoError(t, err)
+	b.Signature = Base64Encode(sig)
+	b.IndepHash, err = BlockIndepHash(b)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, b.IndepHash)
+
+	assert.NoError(t, VerifyBlockSignature(b))
+
+	// any signed field change breaks the signature and the indep hash
+	tampered := b
+	tampered.Reward = "2001"
+	assert.Error(t, VerifyBlockSignature(tampered))
+	assert.NotEqual(t, b.IndepHash, GenerateIndepHash(tampered))
+
+	tampered = b
+	tampered.RewardAddr = hash32
+	assert.Error(t, VerifyBlockSignature(tampered))
+
+	// 2.8 blocks are reported as unsupported, not as valid or invalid
+	b28 := b
+	b28.Height = height_2_8
+	assert.ErrorIs(t, VerifyIndepHash(b28), schema.ErrUnsupportedBlockVersion)
+	assert.ErrorIs(t, VerifyBlockSignature(b28), schema.ErrUnsupportedBlockVersion)
+	_, err = BlockIndepHash(b28)
+	assert.ErrorIs(t, err, schema.ErrUnsupportedBlockVersion)
+	assert.Empty(t, GenerateIndepHash(b28))
+}
+
+func TestBlockJSON(t *testing.T) {
+	body := `{"height":812970,"diff":"115792089039110416381168389782714091630053560834545856346499935466490404274176","reward_pool":0,"weave_size":"123","block_size":45,"cumulative_diff":"6789","tx_tree":[],"txs":["a"]}`
+	b, err := DecodeBlock(body)
+	assert.NoError(t, err)
+	assert.Equal(t, "115792089039110416381168389782714091630053560834545856346499935466490404274176", b.Diff.String())
+	assert.Equal(t, int64(0), b.RewardPool.Int64())
+	assert.Equal(t, int64(123), b.WeaveSize.Int64())
+	assert.Equal(t, int64(45), b.BlockSize.Int64())
+	assert.Equal(t, "6789", b.CumulativeDiff.String())
+
+	// numbers stay numbers and strings stay strings
+	by, err := json.Marshal(b)
+	assert.NoError(t, err)
+	fields := map[string]json.RawMessage{}
+	assert.NoError(t, json.Unmarshal(by, &fields))
+	assert.Equal(t, `"115792089039110416381168389782714091630053560834545856346499935466490404274176"`, string(fields["diff"]))
+	assert.Equal(t, `0`, string(fields["reward_pool"]))
+	assert.Equal(t, `"123"`, string(fields["weave_size"]))
+	assert.Equal(t, `45`, string(fields["block_size"]))
+
+	b2, err := DecodeBlock(string(by))
+	assert.NoError(t, err)
+	assert.Equal(t, b.Diff, b2.Diff)
+	assert.Equal(t, b.BlockSize, b2.BlockSize)
+}
+
+func TestMainnetBlockFixtures(t *testing.T) {
+	// mainnet blocks as served by /block/height/{height}, at the 2.6, 2.6.8 and 2.7 forks
+	for _, height := range []int64{height_2_6, height_2_6_8, height_2_7} {
+		t.Run(fmt.Sprintf("%d", height), func(t *testing.T) {
+			body, err := os.ReadFile(fmt.Sprintf("./testfile/blocks/%d.json", height))
+			if os.IsNotExist(err) {
+				t.Skipf("no fixture, save https://arweave.net/block/height/%d as testfile/blocks/%d.json", height, height)
+			}
+			assert.NoError(t, err)
+			b, err := DecodeBlock(string(body))
+			assert.NoError(t, err)
+			assert.Equal(t, height, b.Height)
+			// the published indep_hash and signature
+			assert.NoError(t, VerifyIndepHash(*b))
+			assert.NoError(t, VerifyBlockSignature(*b))
+		})
+	}
+}
+
+func TestGenerateTxRoot_MainnetBlock(t *testing.T) {
+	// the 2.7 fork block and its tx headers as served by /tx/{id}, in the order of its txs list
+	body, err := os.ReadFile(fmt.Sprintf("./testfile/blocks/%d.json", height_2_7))
+	if os.IsNotExist(err) {
+		t.Skipf("no fixture, save https://arweave.net/block/height/%d as testfile/blocks/%d.json", height_2_7, height_2_7)
+	}
+	assert.NoError(t, err)
+	b, err := DecodeBlock(string(body))
+	assert.NoError(t, err)
+	txsBody, err := os.ReadFile(fmt.Sprintf("./testfile/blocks/%d_txs.json", height_2_7))
+	if os.IsNotExist(err) {
+		t.Skipf("no fixture, save the headers of the txs of block %d as a json array in testfile/blocks/%d_txs.json", height_2_7, height_2_7)
+	}
+	assert.NoError(t, err)
+	txs := make([]*schema.Transaction, 0)
+	assert.NoError(t, json.Unmarshal(txsBody, &txs))
+	assert.Greater(t, len(txs), 1)
+
+	txRoot, err := GenerateTxRoot(txs, b.Height)
+	assert.NoError(t, err)
+	assert.Equal(t, b.TxRoot, txRoot)
+
+	// the tx_root does not depend on the order the txs are given in
+	for i, j := 0, len(txs)-1; i < j; i, j = i+1, j-1 {
+		txs[i], txs[j] = txs[j], txs[i]
+	}
+	txRoot, err = GenerateTxRoot(txs, b.Height)
+	assert.NoError(t, err)
+	assert.Equal(t, b.TxRoot, txRoot)
+}
@@ -14,9 +14,11 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/btcsuite/btcd/btcutil/base58"
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/everFinance/goether"
 	"github.com/permadao/goar/schema"
@@ -533,7 +535,7 @@ func VerifyBundleItem(d schema.BundleItem) error {
 		}
 		return Verify(signMsg, pubKey, sign)
 
-	case schema.ED25519SignType, schema.SolanaSignType:
+	case schema.ED25519SignType:
 		pubkey, err := Base64Decode(d.Owner)
 		if err != nil {
 			return err
@@ -542,6 +544,38 @@ func VerifyBundleItem(d schema.BundleItem) error {
 			return errors.New("verify ed25519 signature failed")
 		}
 
+	case schema.SolanaSignType:
+		pubkey, err := Base64Decode(d.Owner)
+		if err != nil {
+			return err
+		}
+		if len(pubkey) != ed25519.PublicKeySize {
+			return errors.New("invalid solana owner length")
+		}
+		// signed by solana wallets over the hex message, or over the raw message by earlier signers
+		if !ed25519.Verify(pubkey, SolanaMessage(signMsg), sign) && !ed25519.Verify(pubkey, signMsg, sign) {
+			return errors.New("verify solana signature failed")
+		}
+
+	case schema.AptosSignType:
+		pubkey, err := Base64Decode(d.Owner)
+		if err != nil {
+			return err
+		}
+		if len(pubkey) != ed25519.PublicKeySize {
+			return errors.New("invalid aptos owner length")
+		}
+		if !ed25519.Verify(pubkey, AptosMessage(signMsg), sign) {
+			return errors.New("verify aptos signature failed")
+		}
+
+	case schema.MultiAptosSignType:
+		owner, err := Base64Decode(d.Owner)
+		if err != nil {
+			return err
+		}
+		return VerifyAptosMulti(owner, signMsg, sign)
+
 	case schema.EthereumSignType:
 		signer, err := ItemSignerAddr(d)
 		if err != nil {
@@ -555,6 +589,23 @@ func VerifyBundleItem(d schema.BundleItem) error {
 		if signer != addr.String() {
 			return errors.New("verify ecc sign failed")
 		}
+
+	case schema.TypedEthereumSignType:
+		owner, err := Base64Decode(d.Owner)
+		if err != nil {
+			return err
+		}
+		hash, err := TypedEthereumHash(string(owner), signMsg)
+		if err != nil {
+			return err
+		}
+		_, addr, err := goether.Ecrecover(hash, sign)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(string(owner), addr.String()) {
+			return errors.New("verify typed ethereum sign failed")
+		}
 	default:
 		return errors.New("not support the signType")
 	}
@@ -759,6 +810,18 @@ func ItemSignerAddr(b schema.BundleItem) (string, error) {
 			return "", err
 		}
 		return base58.Encode(by), nil
+	case schema.AptosSignType:
+		by, err := Base64Decode(b.Owner)
+		if err != nil {
+			return "", err
+		}
+		return AptosAddress(by), nil
+	case schema.MultiAptosSignType:
+		by, err := Base64Decode(b.Owner)
+		if err != nil {
+			return "", err
+		}
+		return AptosMultiAddress(by)
 	case schema.EthereumSignType:
 		pubkey, err := Base64Decode(b.Owner)
 		if err != nil {
@@ -770,6 +833,15 @@ func ItemSignerAddr(b schema.BundleItem) (string, error) {
 			return "", err
 		}
 		return crypto.PubkeyToAddress(*pk).String(), nil
+	case schema.TypedEthereumSignType:
+		owner, err := Base64Decode(b.Owner)
+		if err != nil {
+			return "", err
+		}
+		if !common.IsHexAddress(string(owner)) {
+			return "", errors.New("invalid typed ethereum owner")
+		}
+		return common.HexToAddress(string(owner)).String(), nil
 
 	default:
 		return "", errors.New("not support the signType")
@@ -28,3 +28,26 @@ func Verify(msg []byte, pubKey *rsa.PublicKey, sign []byte) error {
 		Hash:       crypto.SHA256,
 	})
 }
+
+// SignMessage signs data as ArConnect signMessage/verifyMessage do: RSA-PSS with a 32 bytes salt
+// over the sha256 hash of data. The signed digest is a 32 bytes hash and a tx signature covers a 48 bytes
+// deep hash, so a message signature can not be replayed as a tx signature.
+func SignMessage(data []byte, prvKey *rsa.PrivateKey) ([]byte, error) {
+	msg := sha256.Sum256(data)
+	hashed := sha256.Sum256(msg[:])
+
+	return rsa.SignPSS(rand.Reader, prvKey, crypto.SHA256, hashed[:], &rsa.PSSOptions{
+		SaltLength: 32,
+		Hash:       crypto.SHA256,
+	})
+}
+
+func VerifyMessage(data []byte, pubKey *rsa.PublicKey, sign []byte) error {
+	msg := sha256.Sum256(data)
+	hashed := sha256.Sum256(msg[:])
+
+	return rsa.VerifyPSS(pubKey, crypto.SHA256, hashed[:], sign, &rsa.PSSOptions{
+		SaltLength: 32,
+		Hash:       crypto.SHA256,
+	})
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/everFinance/goether"
+)
+
+// typed ethereum bundle items as signed by arbundles TypedEthereumSigner
+
+const (
+	typedEthereumDomainName    = "Bundlr"
+	typedEthereumDomainVersion = "1"
+	typedEthereumPrimaryType   = "Bundlr"
+)
+
+// TypedEthereumData the EIP-712 typed data signed for a bundle item, address is the lowercase 0x address of the owner
+func TypedEthereumData(address string, signMsg []byte) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+			},
+			typedEthereumPrimaryType: {
+				{Name: "Transaction hash", Type: "bytes"},
+				{Name: "address", Type: "address"},
+			},
+		},
+		PrimaryType: typedEthereumPrimaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:    typedEthereumDomainName,
+			Version: typedEthereumDomainVersion,
+		},
+		Message: apitypes.TypedDataMessage{
+			"Transaction hash": signMsg,
+			"address":          address,
+		},
+	}
+}
+
+// TypedEthereumHash the EIP-712 hash signed for a bundle item of type 7
+func TypedEthereumHash(address string, signMsg []byte) ([]byte, error) {
+	return goether.EIP712Hash(TypedEthereumData(address, signMsg))
+}
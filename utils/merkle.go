@@ -3,6 +3,7 @@ package utils
 import (
 	"bytes"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -300,6 +301,7 @@ type ValidateResult struct {
 	LeftBound  int
 	RightBound int
 	ChunkSize  int
+	DataHash   []byte // leaf data: chunk hash for a data_path, data_root for a tx_path
 }
 
 // 验证 merkle path
@@ -329,10 +331,14 @@ func ValidatePath(id []byte, dest, leftBound, rightBound int, path []byte) (*Val
 				LeftBound:  leftBound,
 				RightBound: rightBound,
 				ChunkSize:  rightBound - leftBound,
+				DataHash:   pathData,
 			}, true
 		}
 		return nil, false
 	}
+	if len(path) < 2*schema.HASH_SIZE+schema.NOTE_SIZE {
+		return nil, false
+	}
 
 	left := path[0:schema.HASH_SIZE]
 	right := path[len(left) : len(left)+schema.HASH_SIZE]
@@ -356,6 +362,63 @@ func ValidatePath(id []byte, dest, leftBound, rightBound int, path []byte) (*Val
 	return nil, false
 }
 
+// VerifyChunk checks a chunk returned by /chunk against the data_root of its transaction.
+// offset is the position of any byte of the chunk relative to the start of the transaction data.
+// Both the data_path and the chunk bytes must match the leaf the path resolves to.
+func VerifyChunk(dataRoot []byte, dataSize, offset int, chunk, dataPath []byte) (*ValidateResult, error) {
+	if len(chunk) == 0 || len(chunk) > schema.MAX_CHUNK_SIZE {
+		return nil, fmt.Errorf("invalid chunk size: %d", len(chunk))
+	}
+	result, err := verifyMerklePath(dataRoot, dataSize, offset, dataPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data_path: %v", err)
+	}
+	if result.ChunkSize != len(chunk) {
+		return nil, fmt.Errorf("chunk size mismatch, proof: %d, chunk: %d", result.ChunkSize, len(chunk))
+	}
+	chunkHash := sha256.Sum256(chunk)
+	if !arrayCompare(chunkHash[:], result.DataHash) {
+		return nil, errors.New("chunk hash does not match data_path leaf")
+	}
+	return result, nil
+}
+
+// VerifyTxPath checks a tx_path against the tx_root of a block.
+// blockSize is the number of bytes the block added to the weave and offset is relative to the block start,
+// the returned DataHash is the data_root of the transaction holding that offset.
+func VerifyTxPath(txRoot []byte, blockSize, offset int, txPath []byte) (*ValidateResult, error) {
+	result, err := verifyMerklePath(txRoot, blockSize, offset, txPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tx_path: %v", err)
+	}
+	return result, nil
+}
+
+func verifyMerklePath(root []byte, size, offset int, path []byte) (*ValidateResult, error) {
+	if len(root) != schema.HASH_SIZE {
+		return nil, fmt.Errorf("root length must be %d", schema.HASH_SIZE)
+	}
+	if size <= 0 {
+		return nil, errors.New("size must be more than 0")
+	}
+	if offset < 0 || offset >= size {
+		return nil, fmt.Errorf("offset %d out of range [0, %d)", offset, size)
+	}
+	leafSize := schema.HASH_SIZE + schema.NOTE_SIZE
+	branchSize := 2*schema.HASH_SIZE + schema.NOTE_SIZE
+	if len(path) < leafSize || (len(path)-leafSize)%branchSize != 0 {
+		return nil, fmt.Errorf("path length incorrect: %d", len(path))
+	}
+	result, ok := ValidatePath(root, offset, 0, size, path)
+	if !ok {
+		return nil, errors.New("proof does not match root")
+	}
+	if offset < result.LeftBound || offset >= result.RightBound {
+		return nil, fmt.Errorf("offset %d not in proven range [%d, %d)", offset, result.LeftBound, result.RightBound)
+	}
+	return result, nil
+}
+
 func bufferToInt(buf []byte) int {
 	value := 0
 	for i := 0; i < len(buf); i++ {
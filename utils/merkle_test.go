@@ -1,9 +1,11 @@
 package utils
 
 import (
+	"crypto/sha256"
 	"os"
 	"testing"
 
+	"github.com/permadao/goar/schema"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,3 +52,65 @@ func TestDecodeEmptyString(t *testing.T) {
 	assert.NoError(t, err)
 	t.Log(by)
 }
+
+func TestVerifyChunk(t *testing.T) {
+	data, err := os.ReadFile("./testfile/1mb.bin")
+	assert.NoError(t, err)
+	chunks, err := GenerateChunks(data)
+	assert.NoError(t, err)
+
+	for i, chunk := range chunks.Chunks {
+		proof := chunks.Proofs[i]
+		chunkBy := data[chunk.MinByteRange:chunk.MaxByteRange]
+		res, err := VerifyChunk(chunks.DataRoot, len(data), proof.Offest, chunkBy, proof.Proof)
+		assert.NoError(t, err)
+		assert.Equal(t, chunk.MinByteRange, res.LeftBound)
+		assert.Equal(t, chunk.MaxByteRange, res.RightBound)
+
+		// any offset inside the chunk resolves to the same leaf
+		_, err = VerifyChunk(chunks.DataRoot, len(data), chunk.MinByteRange, chunkBy, proof.Proof)
+		assert.NoError(t, err)
+	}
+
+	chunk := chunks.Chunks[0]
+	proof := chunks.Proofs[0]
+	chunkBy := append([]byte{}, data[chunk.MinByteRange:chunk.MaxByteRange]...)
+	chunkBy[0] ^= 0xff
+	_, err = VerifyChunk(chunks.DataRoot, len(data), proof.Offest, chunkBy, proof.Proof)
+	assert.Error(t, err)
+
+	// proof of the first chunk can not prove the second one
+	_, err = VerifyChunk(chunks.DataRoot, len(data), chunks.Proofs[1].Offest, data[chunk.MinByteRange:chunk.MaxByteRange], proof.Proof)
+	assert.Error(t, err)
+
+	_, err = VerifyChunk(chunks.DataRoot, len(data), proof.Offest, data[chunk.MinByteRange:chunk.MaxByteRange], proof.Proof[:len(proof.Proof)-1])
+	assert.Error(t, err)
+}
+
+func TestVerifyTxPath(t *testing.T) {
+	dataRoots := make([][]byte, 0, 3)
+	entries := make([]schema.Chunk, 0, 3)
+	end := 0
+	for i, size := range []int{100, 262144, 5} {
+		root := sha256.Sum256([]byte{byte(i)})
+		dataRoots = append(dataRoots, root[:])
+		entries = append(entries, schema.Chunk{
+			DataHash:     root[:],
+			MinByteRange: end,
+			MaxByteRange: end + size,
+		})
+		end += size
+	}
+	root := buildLayer(generateLeaves(entries), 0)
+	proofs := generateProofs(root)
+
+	for i, entry := range entries {
+		res, err := VerifyTxPath(root.ID, end, entry.MaxByteRange-1, proofs[i].Proof)
+		assert.NoError(t, err)
+		assert.Equal(t, dataRoots[i], res.DataHash)
+		assert.Equal(t, entry.MaxByteRange-entry.MinByteRange, res.ChunkSize)
+	}
+
+	_, err := VerifyTxPath(root.ID, end, entries[0].MaxByteRange-1, proofs[1].Proof)
+	assert.Error(t, err)
+}
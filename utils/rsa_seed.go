@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// GenerateRsaKeyFromSeed derives a RSA key deterministically from seed with the scheme of human-crypto-keys,
+// used by arweave-mnemonic-keys: a HMAC-DRBG(sha256) seeded with seed is the prng of node-forge
+// pki.rsa.generateKeyPair, called with a callback as human-crypto-keys does.
+func GenerateRsaKeyFromSeed(seed []byte, bits int) (*rsa.PrivateKey, error) {
+	if len(seed) < 24 {
+		return nil, errors.New("seed too short")
+	}
+	if bits < 512 {
+		return nil, errors.New("rsa key too short")
+	}
+	drbg := newHmacDRBG(seed)
+	e := big.NewInt(65537)
+	one := big.NewInt(1)
+	pBits, qBits := bits-(bits>>1), bits>>1
+
+	// the steps of forge _generateKeyPair: p then q are drawn, the checks of finish draw again p, q or both
+	var p, q *big.Int
+	for {
+		if p == nil {
+			p = forgeFindPrime(drbg, pBits)
+		}
+		if q == nil {
+			q = forgeFindPrime(drbg, qBits)
+		}
+		if p.Cmp(q) < 0 {
+			p, q = q, p
+		}
+		p1 := new(big.Int).Sub(p, one)
+		q1 := new(big.Int).Sub(q, one)
+		if new(big.Int).GCD(nil, nil, p1, e).Cmp(one) != 0 {
+			p = nil
+			continue
+		}
+		if new(big.Int).GCD(nil, nil, q1, e).Cmp(one) != 0 {
+			q = nil
+			continue
+		}
+		phi := new(big.Int).Mul(p1, q1)
+		if new(big.Int).GCD(nil, nil, phi, e).Cmp(one) != 0 {
+			p, q = nil, nil
+			continue
+		}
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != bits {
+			q = nil
+			continue
+		}
+
+		d := new(big.Int).ModInverse(e, phi)
+		key := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{N: n, E: int(e.Int64())},
+			D:         d,
+			Primes:    []*big.Int{p, q},
+		}
+		if err := key.Validate(); err != nil {
+			return nil, err
+		}
+		key.Precompute()
+		return key, nil
+	}
+}
+
+// all primes > 5 are 30k+i with gcd(30, i) = 1, the deltas step through these i from 30k+1
+var gcd30Delta = []int64{6, 4, 2, 4, 2, 4, 6, 2}
+
+// forgeFindPrime the PRIMEINC search of node-forge prime.generateProbablePrime without workers.
+// An overflow draws a new random number and keeps stepping through the deltas from where it was.
+func forgeFindPrime(drbg *hmacDRBG, bits int) *big.Int {
+	num := forgeRandom(drbg, bits)
+	for deltaIdx := 0; ; deltaIdx++ {
+		if num.BitLen() > bits {
+			num = forgeRandom(drbg, bits)
+		}
+		if num.ProbablyPrime(20) {
+			return num
+		}
+		num.Add(num, big.NewInt(gcd30Delta[deltaIdx%8]))
+	}
+}
+
+// forgeRandom a random number of bits with its top bit set, aligned on 30k+1
+func forgeRandom(drbg *hmacDRBG, bits int) *big.Int {
+	// jsbn new BigInteger(bits, rng)
+	b := drbg.generate(bits>>3 + 1)
+	if t := bits & 7; t > 0 {
+		b[0] &= byte(1<<t - 1)
+	} else {
+		b[0] = 0
+	}
+	num := new(big.Int).SetBytes(b)
+	num.SetBit(num, bits-1, 1)
+	return num.Add(num, big.NewInt(31-new(big.Int).Mod(num, big.NewInt(30)).Int64()))
+}
+
+// hmacDRBG NIST SP 800-90A HMAC_DRBG with sha256, without nonce and personalization as the hmac-drbg js package
+type hmacDRBG struct {
+	k, v []byte
+}
+
+func newHmacDRBG(entropy []byte) *hmacDRBG {
+	d := &hmacDRBG{k: make([]byte, sha256.Size), v: make([]byte, sha256.Size)}
+	for i := range d.v {
+		d.v[i] = 0x01
+	}
+	d.update(entropy)
+	return d
+}
+
+func (d *hmacDRBG) hmac(data ...[]byte) []byte {
+	mac := hmac.New(sha256.New, d.k)
+	for _, b := range data {
+		mac.Write(b)
+	}
+	return mac.Sum(nil)
+}
+
+func (d *hmacDRBG) update(seed []byte) {
+	d.k = d.hmac(d.v, []byte{0x00}, seed)
+	d.v = d.hmac(d.v)
+	if seed == nil {
+		return
+	}
+	d.k = d.hmac(d.v, []byte{0x01}, seed)
+	d.v = d.hmac(d.v)
+}
+
+func (d *hmacDRBG) generate(n int) []byte {
+	out := make([]byte, 0, n+sha256.Size)
+	for len(out) < n {
+		d.v = d.hmac(d.v)
+		out = append(out, d.v...)
+	}
+	d.update(nil)
+	return out[:n]
+}
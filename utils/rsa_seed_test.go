@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHmacDRBG(t *testing.T) {
+	// NIST CAVP HMAC_DRBG SHA-256, no prediction resistance, COUNT = 0
+	entropy, _ := hex.DecodeString("ca851911349384bffe89de1cbdc46e6831e44d34a4fb935ee285dd14b71a7488" + "659ba96c601dc69fc902940805ec0ca8")
+	d := newHmacDRBG(entropy)
+	d.generate(128)
+	assert.Equal(t, "e528e9abf2dece54d47c7e75e5fe302149f817ea9fb4bee6f4199697d04d5b89d54fbb978a15b5c443c9ec21036d2460b6f73ebad0dc2aba6e624abf07745bc107694bb7547bb0995f70de25d6b29e2d3011bb19d27676c07162c8b5ccde0668961df86803482cb37ed6d5c0bb8d50cf1f50d476aa0458bdaba806f48be9dcb8", hex.EncodeToString(d.generate(128)))
+}
+
+func TestGenerateRsaKeyFromSeed(t *testing.T) {
+	seed := MnemonicToSeed("legal winner thank year wave sausage worth useful legal winner thank yellow", "")
+	key, err := GenerateRsaKeyFromSeed(seed, 1024)
+	assert.NoError(t, err)
+	assert.Equal(t, 1024, key.N.BitLen())
+	assert.Equal(t, 65537, key.E)
+	assert.True(t, key.Primes[0].Cmp(key.Primes[1]) > 0)
+
+	key2, err := GenerateRsaKeyFromSeed(seed, 1024)
+	assert.NoError(t, err)
+	assert.Equal(t, key.N, key2.N)
+	assert.Equal(t, key.D, key2.D)
+
+	other, err := GenerateRsaKeyFromSeed(MnemonicToSeed("legal winner thank year wave sausage worth useful legal winner thank yellow", "passphrase"), 1024)
+	assert.NoError(t, err)
+	assert.NotEqual(t, key.N, other.N)
+
+	_, err = GenerateRsaKeyFromSeed(seed[:16], 1024)
+	assert.Error(t, err)
+}
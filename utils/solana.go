@@ -0,0 +1,9 @@
+package utils
+
+import "encoding/hex"
+
+// SolanaMessage the message signed by solana wallets for msg with arbundles HexInjectedSolanaSigner,
+// signMessage of wallet adapters signs text so the message is hex encoded
+func SolanaMessage(msg []byte) []byte {
+	return []byte(hex.EncodeToString(msg))
+}
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/permadao/goar/schema"
+)
+
+// ValidateTransaction checks a signed tx against the rules the node enforces before accepting it.
+// All violations are returned, nil means the tx is valid. The data is checked when it is attached to the tx.
+func ValidateTransaction(tx *schema.Transaction) []error {
+	errs := make([]error, 0)
+	add := func(format string, a ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, a...))
+	}
+
+	if tx.Format != 1 && tx.Format != 2 {
+		add("format: unexpected %d", tx.Format)
+	}
+
+	owner, err := Base64Decode(tx.Owner)
+	if err != nil {
+		add("owner: %v", err)
+	} else if len(owner) != schema.TX_OWNER_SIZE {
+		add("owner: %d bytes, expected %d", len(owner), schema.TX_OWNER_SIZE)
+	}
+
+	if lastTx, err := Base64Decode(tx.LastTx); err != nil {
+		add("last_tx: %v", err)
+	} else if l := len(lastTx); !(l == 32 || l == 48 || (l == 0 && tx.Format == 1)) {
+		add("last_tx: %d bytes, expected a 32 bytes tx id or a 48 bytes block hash", l)
+	}
+
+	quantity, ok := new(big.Int).SetString(tx.Quantity, 10)
+	if !ok || quantity.Sign() < 0 {
+		add("quantity: invalid %q", tx.Quantity)
+	}
+	if target, err := Base64Decode(tx.Target); err != nil {
+		add("target: %v", err)
+	} else if len(target) != 0 && len(target) != 32 {
+		add("target: %d bytes, expected 32", len(target))
+	} else if len(target) == 0 && ok && quantity.Sign() > 0 {
+		add("target: required to transfer quantity %s", tx.Quantity)
+	} else if len(target) != 0 && owner != nil {
+		if addr, _ := OwnerToAddress(tx.Owner); addr == tx.Target {
+			add("target: can not be the owner address")
+		}
+	}
+
+	if reward, ok := new(big.Int).SetString(tx.Reward, 10); !ok || reward.Sign() <= 0 {
+		add("reward: must be a positive integer, got %q", tx.Reward)
+	}
+
+	if tags, err := TagsDecode(tx.Tags); err != nil {
+		add("tags: %v", err)
+	} else {
+		size := 0
+		for _, tag := range tags {
+			size += len(tag.Name) + len(tag.Value)
+		}
+		if size > schema.MAX_TX_TAGS_SIZE {
+			add("tags: %d bytes, limit %d", size, schema.MAX_TX_TAGS_SIZE)
+		}
+	}
+
+	errs = append(errs, validateTxData(tx)...)
+	errs = append(errs, validateTxSignature(tx)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateTxData(tx *schema.Transaction) []error {
+	errs := make([]error, 0)
+	dataSize, err := strconv.ParseInt(tx.DataSize, 10, 64)
+	if err != nil || dataSize < 0 {
+		return append(errs, fmt.Errorf("data_size: invalid %q", tx.DataSize))
+	}
+	data, err := Base64Decode(tx.Data)
+	if err != nil {
+		return append(errs, fmt.Errorf("data: %v", err))
+	}
+
+	attached := int64(-1)
+	switch {
+	case tx.DataReader != nil:
+		info, err := tx.DataReader.Stat()
+		if err != nil {
+			return append(errs, fmt.Errorf("data: %v", err))
+		}
+		attached = info.Size()
+	case len(data) > 0 || tx.Format == 1:
+		attached = int64(len(data))
+	}
+	if attached >= 0 && attached != dataSize {
+		errs = append(errs, fmt.Errorf("data_size: %d, attached data is %d bytes", dataSize, attached))
+	}
+
+	if tx.Format == 1 {
+		if dataSize > schema.MAX_TX_V1_DATA_SIZE {
+			errs = append(errs, fmt.Errorf("data_size: %d, format 1 limit %d", dataSize, schema.MAX_TX_V1_DATA_SIZE))
+		}
+		if tx.DataRoot != "" {
+			errs = append(errs, errors.New("data_root: must be empty for format 1"))
+		}
+		return errs
+	}
+
+	dataRoot, err := Base64Decode(tx.DataRoot)
+	if err != nil {
+		return append(errs, fmt.Errorf("data_root: %v", err))
+	}
+	if dataSize == 0 {
+		if len(dataRoot) != 0 {
+			errs = append(errs, errors.New("data_root: must be empty without data"))
+		}
+		return errs
+	}
+	if len(dataRoot) != 32 {
+		return append(errs, fmt.Errorf("data_root: %d bytes, expected 32", len(dataRoot)))
+	}
+	if attached > 0 && attached == dataSize {
+		var chunks schema.Chunks
+		if tx.DataReader != nil {
+			chunks, err = GenerateChunks(tx.DataReader)
+		} else {
+			chunks, err = GenerateChunks(data)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("data: %v", err))
+		} else if root := Base64Encode(chunks.DataRoot); root != tx.DataRoot {
+			errs = append(errs, fmt.Errorf("data_root: %s, attached data has %s", tx.DataRoot, root))
+		}
+	}
+	return errs
+}
+
+func validateTxSignature(tx *schema.Transaction) []error {
+	sig, err := Base64Decode(tx.Signature)
+	if err != nil || len(sig) == 0 {
+		return []error{fmt.Errorf("signature: invalid %q", tx.Signature)}
+	}
+	if id, err := Base64Decode(tx.ID); err != nil || len(id) != 32 {
+		return []error{fmt.Errorf("id: invalid %q", tx.ID)}
+	}
+
+	// format 2 signs the data_root of the header, not the attached data
+	cp := *tx
+	if cp.Format == 2 {
+		cp.Data = ""
+		cp.DataReader = nil
+		cp.Chunks = &schema.Chunks{}
+	}
+	if err = VerifyTransaction(cp); err != nil {
+		return []error{fmt.Errorf("signature: %v", err)}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/permadao/goar/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTransaction(t *testing.T) {
+	prvKey, err := GenerateRsaKey(4096)
+	assert.NoError(t, err)
+	target := Base64Encode(make([]byte, 32))
+	data := make([]byte, 300*1024)
+	data[0] = 1
+	tx := &schema.Transaction{
+		Format:   2,
+		LastTx:   Base64Encode(make([]byte, 48)),
+		Owner:    Base64Encode(prvKey.N.Bytes()),
+		Tags:     TagsEncode([]schema.Tag{{Name: "Content-Type", Value: "text/plain"}}),
+		Target:   target,
+		Quantity: "1000",
+		Data:     Base64Encode(data),
+		DataSize: "307200",
+		Reward:   "100",
+	}
+	assert.NoError(t, SignTransaction(tx, prvKey))
+	assert.Nil(t, ValidateTransaction(tx))
+
+	// header only
+	header := *tx
+	header.Data = ""
+	assert.Nil(t, ValidateTransaction(&header))
+
+	bad := *tx
+	bad.Chunks = nil
+	bad.Target = ""
+	bad.Reward = "0"
+	bad.DataSize = "1"
+	bad.Tags = TagsEncode([]schema.Tag{{Name: "a", Value: strings.Repeat("b", 2048)}})
+	bad.LastTx = Base64Encode(make([]byte, 10))
+	errs := ValidateTransaction(&bad)
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		msgs = append(msgs, strings.SplitN(e.Error(), ":", 2)[0])
+	}
+	assert.Equal(t, []string{"last_tx", "target", "reward", "tags", "data_size", "signature"}, msgs)
+
+	// attached data does not match the data_root
+	other := *tx
+	data[1] = 1
+	other.Data = Base64Encode(data)
+	errs = ValidateTransaction(&other)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "data_root")
+
+	// signature and id
+	forged := *tx
+	forged.ID = Base64Encode(make([]byte, 32))
+	errs = ValidateTransaction(&forged)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "signature")
+}
@@ -1,9 +1,15 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 )
 
+// number of decimals of AR, 1 AR = 10^12 winston
+const ARDecimals = 12
+
 // WinstonToAR 1 Winston = 0.000000000001 AR
 func WinstonToAR(w *big.Int) *big.Float {
 	return new(big.Float).Quo(
@@ -12,9 +18,79 @@ func WinstonToAR(w *big.Int) *big.Float {
 	)
 }
 
-// ARToWinston 1 AR = 1000000000000
+// ARToWinston 1 AR = 1000000000000, a is rounded to 12 decimals
 func ARToWinston(a *big.Float) *big.Int {
-	wFloat := new(big.Float).Mul(a, new(big.Float).SetUint64(1000000000000))
-	w, _ := new(big.Int).SetString(wFloat.Text('f', 0), 10)
+	w, _ := ARStringToWinston(a.Text('f', ARDecimals))
 	return w
 }
+
+// ARStringToWinston exact conversion of a decimal AR string, eg: "0.1", with at most 12 decimals
+func ARStringToWinston(ar string) (*big.Int, error) {
+	neg := strings.HasPrefix(ar, "-")
+	digits := strings.TrimLeft(ar, "+-")
+	if len(ar)-len(digits) > 1 {
+		return nil, fmt.Errorf("invalid AR amount: %s", ar)
+	}
+	intPart, frac, _ := strings.Cut(digits, ".")
+	if intPart == "" && frac == "" {
+		return nil, fmt.Errorf("invalid AR amount: %s", ar)
+	}
+	if len(frac) > ARDecimals {
+		return nil, fmt.Errorf("AR amount has more than %d decimals: %s", ARDecimals, ar)
+	}
+	digits = intPart + frac + strings.Repeat("0", ARDecimals-len(frac))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("invalid AR amount: %s", ar)
+		}
+	}
+	w, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, errors.New("invalid AR amount")
+	}
+	if neg {
+		w.Neg(w)
+	}
+	return w, nil
+}
+
+// WinstonToARString exact decimal AR string of w, without trailing zeros, eg: "0.1"
+func WinstonToARString(w *big.Int) string {
+	s := new(big.Int).Abs(w).String()
+	if len(s) <= ARDecimals {
+		s = strings.Repeat("0", ARDecimals-len(s)+1) + s
+	}
+	intPart, frac := s[:len(s)-ARDecimals], strings.TrimRight(s[len(s)-ARDecimals:], "0")
+	if frac != "" {
+		intPart += "." + frac
+	}
+	if w.Sign() < 0 {
+		return "-" + intPart
+	}
+	return intPart
+}
+
+// MulReward reward * m, rounded down to a winston
+func MulReward(reward *big.Int, m *big.Rat) *big.Int {
+	r := new(big.Int).Mul(reward, m.Num())
+	return r.Quo(r, m.Denom())
+}
+
+// SpeedUpReward reward * (100 + speedFactor) / 100, eg: speedFactor = 10, reward = 1.1 * reward
+func SpeedUpReward(reward *big.Int, speedFactor int64) *big.Int {
+	return MulReward(reward, big.NewRat(100+speedFactor, 100))
+}
+
+// ARToUSD converts with a usd_to_ar_rate [dividend, divisor], where 1 USD = dividend / divisor AR
+func ARToUSD(ar *big.Float, usdToArRate []string) *big.Float {
+	if len(usdToArRate) != 2 {
+		return nil
+	}
+	dividend, ok1 := new(big.Float).SetString(usdToArRate[0])
+	divisor, ok2 := new(big.Float).SetString(usdToArRate[1])
+	if !ok1 || !ok2 || dividend.Sign() == 0 {
+		return nil
+	}
+	usd := new(big.Float).Mul(ar, divisor)
+	return usd.Quo(usd, dividend)
+}
@@ -26,3 +26,42 @@ func TestARToWinston(t *testing.T) {
 	w = ARToWinston(a)
 	assert.Equal(t, "1", w.String())
 }
+
+func TestSpeedUpReward(t *testing.T) {
+	r, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	assert.Equal(t, "135802467913580246791358024679", SpeedUpReward(r, 10).String())
+	assert.Equal(t, "1", SpeedUpReward(big.NewInt(1), 50).String())
+	assert.Equal(t, "1150", MulReward(big.NewInt(1000), big.NewRat(115, 100)).String())
+	assert.Equal(t, "333", MulReward(big.NewInt(1000), big.NewRat(1, 3)).String())
+}
+
+func TestARToUSD(t *testing.T) {
+	// 1 USD = 1/8 AR
+	usd := ARToUSD(big.NewFloat(2), []string{"1", "8"})
+	assert.Equal(t, "16", usd.String())
+	assert.Nil(t, ARToUSD(big.NewFloat(2), []string{"0", "8"}))
+	assert.Nil(t, ARToUSD(big.NewFloat(2), nil))
+}
+
+func TestARStringToWinston(t *testing.T) {
+	for _, c := range []struct{ ar, winston, format string }{
+		{"0.1", "100000000000", "0.1"},
+		{"1.50", "1500000000000", "1.5"},
+		{"-2", "-2000000000000", "-2"},
+		{".000000000001", "1", "0.000000000001"},
+		{"123456789.123456", "123456789123456000000", "123456789.123456"},
+	} {
+		got, err := ARStringToWinston(c.ar)
+		assert.NoError(t, err)
+		assert.Equal(t, c.winston, got.String())
+		assert.Equal(t, c.format, WinstonToARString(got))
+	}
+	for _, ar := range []string{"", ".", "1.0000000000001", "1e5", "--1", "1.2.3"} {
+		_, err := ARStringToWinston(ar)
+		assert.Error(t, err, ar)
+	}
+	assert.Equal(t, "0", WinstonToARString(big.NewInt(0)))
+	assert.Equal(t, "0.000000000001", WinstonToARString(big.NewInt(1)))
+	assert.Equal(t, "-0.1", WinstonToARString(big.NewInt(-100000000000)))
+	assert.Equal(t, "100000000000", ARToWinston(big.NewFloat(0.1)).String())
+}
@@ -13,6 +13,13 @@ import (
 type Wallet struct {
 	Client *Client
 	Signer *Signer
+	// TxSigner signs the txs of the wallet instead of Signer when set, eg: a RemoteSigner
+	TxSigner TxSigner
+
+	// Preflight checks before signing that the balance covers reward + quantity of the tx,
+	// less the txs sent by this wallet and not mined yet
+	Preflight bool
+	spends    pendingSpends
 }
 
 func NewWallet(b []byte, clientUrl string, proxyUrl ...string) (w *Wallet, err error) {
@@ -31,6 +38,17 @@ func NewWalletWithSigner(signer *Signer, clientUrl string, proxyUrl ...string) *
 	}
 }
 
+// NewWalletWithTxSigner wallet signing with any TxSigner, eg: a RemoteSigner
+func NewWalletWithTxSigner(signer TxSigner, clientUrl string, proxyUrl ...string) *Wallet {
+	if s, ok := signer.(*Signer); ok {
+		return NewWalletWithSigner(s, clientUrl, proxyUrl...)
+	}
+	return &Wallet{
+		Client:   NewClient(clientUrl, proxyUrl...),
+		TxSigner: signer,
+	}
+}
+
 // proxyUrl: option
 func NewWalletFromPath(path string, clientUrl string, proxyUrl ...string) (*Wallet, error) {
 	b, err := os.ReadFile(path)
@@ -51,23 +69,40 @@ func NewWalletFrom(path string, clientUrl string, proxyUrl ...string) (*Wallet,
 }
 
 func (w *Wallet) Owner() string {
-	return w.Signer.Owner()
+	return w.txSigner().Owner()
 }
 
+func (w *Wallet) txSigner() TxSigner {
+	if w.TxSigner != nil {
+		return w.TxSigner
+	}
+	return w.Signer
+}
+
+// SendAR amount is rounded to a winston, use SendAmount with ParseAR for exact decimal amounts
 func (w *Wallet) SendAR(amount *big.Float, target string, tags []schema.Tag) (schema.Transaction, error) {
-	return w.SendWinstonSpeedUp(utils.ARToWinston(amount), target, tags, 0)
+	return w.SendAmountSpeedUp(NewAmount(utils.ARToWinston(amount)), target, tags, 0)
 }
 
 func (w *Wallet) SendARSpeedUp(amount *big.Float, target string, tags []schema.Tag, speedFactor int64) (schema.Transaction, error) {
-	return w.SendWinstonSpeedUp(utils.ARToWinston(amount), target, tags, speedFactor)
+	return w.SendAmountSpeedUp(NewAmount(utils.ARToWinston(amount)), target, tags, speedFactor)
 }
 
 func (w *Wallet) SendWinston(amount *big.Int, target string, tags []schema.Tag) (schema.Transaction, error) {
-	return w.SendWinstonSpeedUp(amount, target, tags, 0)
+	return w.SendAmountSpeedUp(NewAmount(amount), target, tags, 0)
 }
 
 func (w *Wallet) SendWinstonSpeedUp(amount *big.Int, target string, tags []schema.Tag, speedFactor int64) (schema.Transaction, error) {
-	reward, err := w.Client.GetTransactionPrice(0, &target)
+	return w.SendAmountSpeedUp(NewAmount(amount), target, tags, speedFactor)
+}
+
+func (w *Wallet) SendAmount(amount Amount, target string, tags []schema.Tag) (schema.Transaction, error) {
+	return w.SendAmountSpeedUp(amount, target, tags, 0)
+}
+
+// SendAmountSpeedUp sends amount to target, the other Send AR and winston methods delegate to it
+func (w *Wallet) SendAmountSpeedUp(amount Amount, target string, tags []schema.Tag, speedFactor int64) (schema.Transaction, error) {
+	reward, err := w.speedUpReward(0, &target, speedFactor)
 	if err != nil {
 		return schema.Transaction{}, err
 	}
@@ -75,11 +110,11 @@ func (w *Wallet) SendWinstonSpeedUp(amount *big.Int, target string, tags []schem
 	tx := &schema.Transaction{
 		Format:   2,
 		Target:   target,
-		Quantity: amount.String(),
+		Quantity: amount.Winston().String(),
 		Tags:     utils.TagsEncode(tags),
 		Data:     "",
 		DataSize: "0",
-		Reward:   fmt.Sprintf("%d", reward*(100+speedFactor)/100),
+		Reward:   reward,
 	}
 
 	return w.SendTransaction(tx)
@@ -96,7 +131,7 @@ func (w *Wallet) SendDataStream(data *os.File, tags []schema.Tag) (schema.Transa
 // SendDataSpeedUp set speedFactor for speed up
 // eg: speedFactor = 10, reward = 1.1 * reward
 func (w *Wallet) SendDataSpeedUp(data []byte, tags []schema.Tag, speedFactor int64) (schema.Transaction, error) {
-	reward, err := w.Client.GetTransactionPrice(len(data), nil)
+	reward, err := w.speedUpReward(len(data), nil, speedFactor)
 	if err != nil {
 		return schema.Transaction{}, err
 	}
@@ -108,7 +143,7 @@ func (w *Wallet) SendDataSpeedUp(data []byte, tags []schema.Tag, speedFactor int
 		Tags:     utils.TagsEncode(tags),
 		Data:     utils.Base64Encode(data),
 		DataSize: fmt.Sprintf("%d", len(data)),
-		Reward:   fmt.Sprintf("%d", reward*(100+speedFactor)/100),
+		Reward:   reward,
 	}
 
 	return w.SendTransaction(tx)
@@ -119,7 +154,7 @@ func (w *Wallet) SendDataStreamSpeedUp(data *os.File, tags []schema.Tag, speedFa
 	if err != nil {
 		return schema.Transaction{}, err
 	}
-	reward, err := w.Client.GetTransactionPrice(int(fileInfo.Size()), nil)
+	reward, err := w.speedUpReward(int(fileInfo.Size()), nil, speedFactor)
 	if err != nil {
 		return schema.Transaction{}, err
 	}
@@ -132,14 +167,13 @@ func (w *Wallet) SendDataStreamSpeedUp(data *os.File, tags []schema.Tag, speedFa
 		Data:       "",
 		DataReader: data,
 		DataSize:   fmt.Sprintf("%d", fileInfo.Size()),
-		Reward:     fmt.Sprintf("%d", reward*(100+speedFactor)/100),
+		Reward:     reward,
 	}
 
 	return w.SendTransaction(tx)
 }
 
 func (w *Wallet) SendDataConcurrentSpeedUp(ctx context.Context, concurrentNum int, data interface{}, tags []schema.Tag, speedFactor int64) (schema.Transaction, error) {
-	var reward int64
 	var dataLen int
 	isByteArr := true
 	if _, isByteArr = data.([]byte); isByteArr {
@@ -151,7 +185,7 @@ func (w *Wallet) SendDataConcurrentSpeedUp(ctx context.Context, concurrentNum in
 		}
 		dataLen = int(fileInfo.Size())
 	}
-	reward, err := w.Client.GetTransactionPrice(dataLen, nil)
+	reward, err := w.speedUpReward(dataLen, nil, speedFactor)
 	if err != nil {
 		return schema.Transaction{}, err
 	}
@@ -162,7 +196,7 @@ func (w *Wallet) SendDataConcurrentSpeedUp(ctx context.Context, concurrentNum in
 		Quantity: "0",
 		Tags:     utils.TagsEncode(tags),
 		DataSize: fmt.Sprintf("%d", dataLen),
-		Reward:   fmt.Sprintf("%d", reward*(100+speedFactor)/100),
+		Reward:   reward,
 	}
 
 	if isByteArr {
@@ -176,32 +210,57 @@ func (w *Wallet) SendDataConcurrentSpeedUp(ctx context.Context, concurrentNum in
 
 // SendTransaction: if send success, should return pending
 func (w *Wallet) SendTransaction(tx *schema.Transaction) (schema.Transaction, error) {
-	uploader, err := w.getUploader(tx)
+	uploader, spend, err := w.getUploader(tx)
 	if err != nil {
 		return schema.Transaction{}, err
 	}
 	err = uploader.Once()
+	w.trackFunds(spend, tx, uploader.TxPosted)
 	return *tx, err
 }
 
 func (w *Wallet) SendTransactionConcurrent(ctx context.Context, concurrentNum int, tx *schema.Transaction) (schema.Transaction, error) {
-	uploader, err := w.getUploader(tx)
+	uploader, spend, err := w.getUploader(tx)
 	if err != nil {
 		return schema.Transaction{}, err
 	}
 	err = uploader.ConcurrentOnce(ctx, concurrentNum)
+	w.trackFunds(spend, tx, uploader.TxPosted)
 	return *tx, err
 }
 
-func (w *Wallet) getUploader(tx *schema.Transaction) (*TransactionUploader, error) {
+// speedUpReward network price of the tx increased by speedFactor percent
+func (w *Wallet) speedUpReward(dataSize int, target *string, speedFactor int64) (string, error) {
+	price, err := w.Client.GetTransactionPriceWinston(dataSize, target)
+	if err != nil {
+		return "", err
+	}
+	return utils.SpeedUpReward(price, speedFactor).String(), nil
+}
+
+func (w *Wallet) getUploader(tx *schema.Transaction) (*TransactionUploader, *pendingSpend, error) {
+	var spend *pendingSpend
+	if w.Preflight {
+		var err error
+		if spend, err = w.reserveFunds(tx); err != nil {
+			return nil, nil, err
+		}
+	}
 	anchor, err := w.Client.GetTransactionAnchor()
 	if err != nil {
-		return nil, err
+		w.releaseFunds(spend)
+		return nil, nil, err
 	}
 	tx.LastTx = anchor
 	tx.Owner = w.Owner()
-	if err = w.Signer.SignTx(tx); err != nil {
-		return nil, err
+	if err = w.txSigner().SignTx(tx); err != nil {
+		w.releaseFunds(spend)
+		return nil, nil, err
+	}
+	uploader, err := CreateUploader(w.Client, tx, nil)
+	if err != nil {
+		w.releaseFunds(spend)
+		return nil, nil, err
 	}
-	return CreateUploader(w.Client, tx, nil)
+	return uploader, spend, nil
 }
@@ -0,0 +1,121 @@
+package goar
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+)
+
+// ManagedSendOptions of Wallet.SendTransactionManaged, zero values use the defaults
+type ManagedSendOptions struct {
+	MinConfirmations int // default 1
+
+	// while pending the tx is posted to RebroadcastPeers more peers every RebroadcastInterval, 0 peers disables it
+	RebroadcastPeers    int64
+	RebroadcastInterval time.Duration // default 10 minutes
+
+	// number of times a dropped tx is re-anchored, re-signed and resubmitted, default 3, < 0 disables it
+	MaxResubmits int
+	// reward percent added on every resubmission, eg: 10 pays 1.1 * reward on the first one, 1.2 * on the second
+	SpeedFactorStep int64
+}
+
+func (o ManagedSendOptions) withDefaults() ManagedSendOptions {
+	if o.MinConfirmations <= 0 {
+		o.MinConfirmations = 1
+	}
+	if o.RebroadcastInterval <= 0 {
+		o.RebroadcastInterval = 10 * time.Minute
+	}
+	if o.MaxResubmits == 0 {
+		o.MaxResubmits = 3
+	}
+	return o
+}
+
+// SendTransactionManaged sends tx and keeps it with its data until it is confirmed.
+// While pending the header and chunks are rebroadcast to other peers; once the anchor expired
+// the tx is re-anchored, re-signed with a higher reward and resubmitted.
+// The result lists the lineage of tx ids, it is returned with the error too.
+func (w *Wallet) SendTransactionManaged(ctx context.Context, tx *schema.Transaction, opts ManagedSendOptions) (*schema.ManagedTxResult, error) {
+	opts = opts.withDefaults()
+	baseReward, ok := new(big.Int).SetString(tx.Reward, 10)
+	if !ok {
+		return nil, errors.New("invalid tx reward")
+	}
+
+	res := &schema.ManagedTxResult{TxIds: make([]string, 0, 1)}
+	if _, err := w.SendTransaction(tx); err != nil {
+		return res, err
+	}
+	res.TxIds = append(res.TxIds, tx.ID)
+
+	anchors := &anchorCache{heights: make(map[string]int64)}
+	for resubmits := 0; ; resubmits++ {
+		status, err := w.waitManaged(ctx, tx, opts, anchors)
+		if err == nil {
+			res.Status = status
+			return res, nil
+		}
+		if err != schema.ErrTxDropped || resubmits >= opts.MaxResubmits {
+			return res, err
+		}
+
+		log.Warn("tx dropped, resubmit", "txId", tx.ID, "resubmits", resubmits+1)
+		if err = w.bumpReward(tx, baseReward, opts.SpeedFactorStep*int64(resubmits+1)); err != nil {
+			return res, err
+		}
+		if _, err = w.SendTransaction(tx); err != nil {
+			return res, err
+		}
+		res.TxIds = append(res.TxIds, tx.ID)
+	}
+}
+
+func (w *Wallet) waitManaged(ctx context.Context, tx *schema.Transaction, opts ManagedSendOptions, anchors *anchorCache) (*schema.TxStatus, error) {
+	t := newTxTrackerOf(tx)
+	lastBroadcast := time.Now()
+	for {
+		status, done, err := w.Client.checkTx(t, opts.MinConfirmations, anchors, nil)
+		if err != nil || done {
+			return status, err
+		}
+		if status == nil && opts.RebroadcastPeers > 0 && time.Since(lastBroadcast) >= opts.RebroadcastInterval {
+			if err = w.Client.BroadcastTx(tx, opts.RebroadcastPeers); err != nil {
+				log.Warn("rebroadcast tx failed", "err", err, "txId", tx.ID)
+			}
+			lastBroadcast = time.Now()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(t.backoff()):
+		}
+	}
+}
+
+// bumpReward sets the tx reward to the max of the original and the current price, increased by speedFactor percent
+func (w *Wallet) bumpReward(tx *schema.Transaction, baseReward *big.Int, speedFactor int64) error {
+	dataSize, err := strconv.Atoi(tx.DataSize)
+	if err != nil {
+		return err
+	}
+	var target *string
+	if tx.Target != "" {
+		target = &tx.Target
+	}
+	price, err := w.Client.GetTransactionPriceWinston(dataSize, target)
+	if err != nil {
+		return err
+	}
+	if price.Cmp(baseReward) < 0 {
+		price = baseReward
+	}
+	tx.Reward = utils.SpeedUpReward(price, speedFactor).String()
+	return nil
+}
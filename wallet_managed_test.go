@@ -0,0 +1,71 @@
+package goar
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWallet_SendTransactionManaged(t *testing.T) {
+	anchor1 := utils.Base64Encode(append(make([]byte, 47), 1))
+	anchor2 := utils.Base64Encode(append(make([]byte, 47), 2))
+	node := &testTxNode{
+		height:   100,
+		statuses: map[string]*schema.TxStatus{},
+		anchors:  map[string]string{},
+		blocks:   map[string]int64{anchor1: 90, anchor2: 140},
+		anchor:   anchor1,
+	}
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+	w := NewWalletWithSigner(testWallet.Signer, srv.URL)
+
+	go func() {
+		for {
+			time.Sleep(20 * time.Millisecond)
+			node.lock.Lock()
+			switch len(node.submitted) {
+			case 1:
+				// first tx expires
+				if node.height == 100 {
+					node.height = 141
+					node.anchor = anchor2
+					delete(node.statuses, node.submitted[0].ID)
+				}
+			case 2:
+				node.statuses[node.submitted[1].ID] = &schema.TxStatus{BlockHeight: 142, BlockIndepHash: "b142", NumberOfConfirmations: 1}
+				node.lock.Unlock()
+				return
+			}
+			node.lock.Unlock()
+		}
+	}()
+
+	tx := &schema.Transaction{
+		Format:   2,
+		Quantity: "0",
+		Data:     utils.Base64Encode([]byte("managed")),
+		DataSize: "7",
+		Reward:   "500",
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := w.SendTransactionManaged(ctx, tx, ManagedSendOptions{SpeedFactorStep: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 142, res.Status.BlockHeight)
+	assert.Len(t, res.TxIds, 2)
+	assert.Len(t, node.submitted, 2)
+	assert.Equal(t, node.submitted[0].ID, res.TxIds[0])
+	assert.Equal(t, node.submitted[1].ID, res.TxIds[1])
+	assert.Equal(t, anchor1, node.submitted[0].LastTx)
+	assert.Equal(t, anchor2, node.submitted[1].LastTx)
+	// price 1000 > original reward 500, bumped by 10%
+	assert.Equal(t, "1100", node.submitted[1].Reward)
+	assert.NoError(t, utils.VerifyTransaction(*node.submitted[1]))
+	assert.Equal(t, tx.ID, res.TxIds[1])
+}
@@ -0,0 +1,150 @@
+package goar
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/permadao/goar/schema"
+)
+
+// pendingSpends reward + quantity of the txs sent by a wallet and not mined yet
+type pendingSpends struct {
+	lock    sync.Mutex
+	list    []*pendingSpend
+	anchors *anchorCache
+}
+
+type pendingSpend struct {
+	amount   *big.Int
+	tracker  *txTracker // nil while the tx is signed and uploaded
+	checking bool       // the tx status is being checked by a reservation
+}
+
+// reserveFunds checks the balance covers the tx and reserves its cost until it is mined or dropped.
+// The node is queried without holding the lock, which only guards reading and reserving amounts.
+func (w *Wallet) reserveFunds(tx *schema.Transaction) (*pendingSpend, error) {
+	required, err := txCost(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// pruned before the balance is fetched: a tx mined in between is counted twice rather than never
+	w.prunePendingSpends()
+	addr, err := TxSignerAddress(w.txSigner())
+	if err != nil {
+		return nil, err
+	}
+	balance, err := w.Client.GetWalletWinstonBalance(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &w.spends
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	pending := new(big.Int)
+	for _, spend := range s.list {
+		pending.Add(pending, spend.amount)
+	}
+	total := new(big.Int).Add(pending, required)
+	if total.Cmp(balance) > 0 {
+		return nil, &schema.ErrInsufficientFunds{
+			Balance:   balance,
+			Pending:   pending,
+			Required:  required,
+			Shortfall: total.Sub(total, balance),
+		}
+	}
+	spend := &pendingSpend{amount: required}
+	s.list = append(s.list, spend)
+	return spend, nil
+}
+
+// prunePendingSpends drops the mined or dropped txs, spends checked by another reservation are skipped
+func (w *Wallet) prunePendingSpends() {
+	s := &w.spends
+	s.lock.Lock()
+	if s.anchors == nil {
+		s.anchors = &anchorCache{heights: make(map[string]int64)}
+	}
+	anchors := s.anchors
+	checks := make([]*pendingSpend, 0, len(s.list))
+	for _, spend := range s.list {
+		if spend.tracker != nil && !spend.checking {
+			spend.checking = true
+			checks = append(checks, spend)
+		}
+	}
+	s.lock.Unlock()
+	if len(checks) == 0 {
+		return
+	}
+
+	settled := make(map[*pendingSpend]bool, len(checks))
+	for _, spend := range checks {
+		// once mined the balance already accounts for the tx
+		_, done, err := w.Client.checkTx(spend.tracker, 1, anchors, nil)
+		settled[spend] = done || err == schema.ErrTxDropped
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	list := s.list[:0]
+	for _, spend := range s.list {
+		done, checked := settled[spend]
+		if done {
+			continue
+		}
+		if checked {
+			spend.checking = false
+		}
+		list = append(list, spend)
+	}
+	s.list = list
+}
+
+// trackFunds watches the tx of a reservation once posted, the reservation is released otherwise
+func (w *Wallet) trackFunds(spend *pendingSpend, tx *schema.Transaction, posted bool) {
+	if spend == nil {
+		return
+	}
+	if !posted {
+		w.releaseFunds(spend)
+		return
+	}
+	w.spends.lock.Lock()
+	defer w.spends.lock.Unlock()
+	spend.tracker = newTxTrackerOf(tx)
+}
+
+func (w *Wallet) releaseFunds(spend *pendingSpend) {
+	if spend == nil {
+		return
+	}
+	s := &w.spends
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i, sp := range s.list {
+		if sp == spend {
+			s.list = append(s.list[:i], s.list[i+1:]...)
+			return
+		}
+	}
+}
+
+// txCost reward + quantity in winston
+func txCost(tx *schema.Transaction) (*big.Int, error) {
+	reward, err := ParseWinston(tx.Reward)
+	if err != nil {
+		return nil, err
+	}
+	cost := reward.Winston()
+	if tx.Quantity != "" {
+		quantity, err := ParseWinston(tx.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		cost.Add(cost, quantity.Winston())
+	}
+	return cost, nil
+}
@@ -0,0 +1,97 @@
+package goar
+
+import (
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/permadao/goar/schema"
+	"github.com/permadao/goar/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWallet_Preflight(t *testing.T) {
+	anchor := utils.Base64Encode(make([]byte, 48))
+	node := &testTxNode{
+		height:   100,
+		statuses: map[string]*schema.TxStatus{},
+		anchors:  map[string]string{},
+		blocks:   map[string]int64{anchor: 99},
+		anchor:   anchor,
+		balance:  "3000",
+	}
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+	w := NewWalletWithSigner(testWallet.Signer, srv.URL)
+	w.Preflight = true
+
+	// reward 1000 + quantity 1000
+	tx1, err := w.SendWinston(big.NewInt(1000), testWallet.Signer.Address, nil)
+	assert.NoError(t, err)
+	// reward 1000, all the balance is spent
+	_, err = w.SendData([]byte("preflight"), nil)
+	assert.NoError(t, err)
+
+	_, err = w.SendData([]byte("preflight"), nil)
+	insufficient := &schema.ErrInsufficientFunds{}
+	assert.True(t, errors.As(err, &insufficient))
+	assert.Equal(t, "3000", insufficient.Pending.String())
+	assert.Equal(t, "1000", insufficient.Shortfall.String())
+	assert.Len(t, node.submitted, 2)
+
+	// tx1 mined, the balance accounts for it
+	node.set(func() {
+		node.statuses[tx1.ID] = &schema.TxStatus{BlockHeight: 101, NumberOfConfirmations: 1}
+		node.balance = "1500"
+	})
+	_, err = w.SendData([]byte("preflight"), nil)
+	assert.True(t, errors.As(err, &insufficient))
+	assert.Equal(t, "1000", insufficient.Pending.String())
+	assert.Equal(t, "500", insufficient.Shortfall.String())
+
+	node.set(func() { node.balance = "2000" })
+	_, err = w.SendData([]byte("preflight"), nil)
+	assert.NoError(t, err)
+	assert.Len(t, node.submitted, 3)
+}
+
+func TestWallet_PreflightConcurrent(t *testing.T) {
+	anchor := utils.Base64Encode(make([]byte, 48))
+	node := &testTxNode{
+		height:   100,
+		statuses: map[string]*schema.TxStatus{},
+		anchors:  map[string]string{},
+		blocks:   map[string]int64{anchor: 99},
+		anchor:   anchor,
+		balance:  "3000",
+	}
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+	w := NewWalletWithSigner(testWallet.Signer, srv.URL)
+	w.Preflight = true
+
+	// reservations are atomic although the node is queried concurrently
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = w.SendData([]byte("preflight"), nil)
+		}(i)
+	}
+	wg.Wait()
+	sent := 0
+	for _, err := range errs {
+		if err == nil {
+			sent++
+		} else {
+			insufficient := &schema.ErrInsufficientFunds{}
+			assert.True(t, errors.As(err, &insufficient))
+		}
+	}
+	assert.Equal(t, 3, sent)
+	assert.Len(t, node.submitted, 3)
+}